@@ -0,0 +1,231 @@
+/*
+Package lru provides a goroutine-safe least-recently-used cache.
+
+A Cache is a map from keys to list elements plus a lists.Doubly that keeps
+its entries in order of use, most recent first.  Get and Put move an entry to
+the front of the list in O(1) through its lists.Element, and once the cache
+is over capacity the entries at the back of the list are evicted.
+
+Capacity is a number of entries, a number of bytes as measured by a size
+function, or both:
+
+	thumbnails := lru.New[string, []byte](1000,
+		lru.WithMaxBytes(64<<20, func(key string, value []byte) int64 {
+			return int64(len(value))
+		}),
+		lru.OnEvict(func(key string, value []byte) {
+			log.Println("evicted", key)
+		}))
+*/
+package lru
+
+import (
+	"sync"
+
+	"github.com/suicidejack/go-various/lists"
+)
+
+// Cache goroutine-safe least-recently-used cache mapping keys of type K to
+// values of type V
+type Cache[K comparable, V any] struct {
+	mu    sync.Mutex
+	items map[K]*lists.Element[*entry[K, V]]
+	// order holds the entries from most to least recently used.  It is
+	// guarded by mu rather than its own lock.
+	order      *lists.Doubly[*entry[K, V]]
+	maxEntries int
+	maxBytes   int64
+	sizeOf     func(key K, value V) int64
+	bytes      int64
+	onEvict    func(key K, value V)
+	stats      Stats
+}
+
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+	size  int64
+}
+
+// Stats counts how a Cache has been used
+type Stats struct {
+	// Hits is the number of calls to Get that found their key
+	Hits uint64
+	// Misses is the number of calls to Get that didn't
+	Misses uint64
+	// Evictions is the number of entries removed to stay within capacity
+	Evictions uint64
+}
+
+// Option configures a Cache when it is created
+type Option[K comparable, V any] func(*Cache[K, V])
+
+// WithMaxBytes limits the cache to maxBytes, as measured by calling size
+// with each entry when it is added.  size should be cheap and must return
+// the same result for the same entry every time.
+func WithMaxBytes[K comparable, V any](maxBytes int64, size func(key K, value V) int64) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.maxBytes = maxBytes
+		c.sizeOf = size
+	}
+}
+
+// OnEvict calls f with every entry evicted to keep the cache within its
+// capacity, least recently used first.  f isn't called for entries removed
+// by Remove or replaced by Put.  It is called after the cache's lock has been
+// released, so f may use the cache.
+func OnEvict[K comparable, V any](f func(key K, value V)) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.onEvict = f
+	}
+}
+
+// New creates a new empty cache holding at most maxEntries entries.
+// maxEntries may be 0 if WithMaxBytes limits the cache instead.  Panics if
+// the cache would have no limit at all.
+func New[K comparable, V any](maxEntries int, opts ...Option[K, V]) *Cache[K, V] {
+	c := &Cache[K, V]{
+		items:      map[K]*lists.Element[*entry[K, V]]{},
+		order:      lists.NewDoublyOf[*entry[K, V]](lists.WithoutLocking()),
+		maxEntries: maxEntries,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.maxEntries < 1 && c.maxBytes < 1 {
+		panic("lru: cache must have a positive capacity")
+	}
+	return c
+}
+
+// Get returns the value stored for key and marks it as the most recently
+// used entry.  ok is false if there is no entry for key.
+//
+// Runtime: O(1)
+func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.items[key]
+	if !ok {
+		c.stats.Misses++
+		return value, false
+	}
+	c.stats.Hits++
+	c.order.MoveToHead(e)
+	ent, _ := c.order.Value(e)
+	return ent.value, true
+}
+
+// Peek returns the value stored for key like Get, but without marking it as
+// used or counting a hit or miss
+//
+// Runtime: O(1)
+func (c *Cache[K, V]) Peek(key K) (value V, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.items[key]
+	if !ok {
+		return value, false
+	}
+	ent, _ := c.order.Value(e)
+	return ent.value, true
+}
+
+// Put stores value for key, replacing any value already stored, and marks it
+// as the most recently used entry.  Then, while the cache is over capacity,
+// it evicts the least recently used entry, which may be the new one if it is
+// bigger than WithMaxBytes allows on its own.
+//
+// Runtime: O(1), plus O(1) for each entry evicted
+func (c *Cache[K, V]) Put(key K, value V) {
+	var size int64
+	if c.sizeOf != nil {
+		size = c.sizeOf(key, value)
+	}
+	c.mu.Lock()
+	if e, ok := c.items[key]; ok {
+		ent, _ := c.order.Value(e)
+		c.bytes += size - ent.size
+		ent.value, ent.size = value, size
+		c.order.MoveToHead(e)
+	} else {
+		// order is unbounded and never closed, so the push can't fail
+		e, _ := c.order.PushHeadElement(&entry[K, V]{key: key, value: value, size: size})
+		c.items[key] = e
+		c.bytes += size
+	}
+	evicted := c.evict()
+	onEvict := c.onEvict
+	c.mu.Unlock()
+	if onEvict != nil {
+		for _, ent := range evicted {
+			onEvict(ent.key, ent.value)
+		}
+	}
+}
+
+// Remove deletes the entry for key and returns its value.  ok is false if
+// there was no entry for key.
+//
+// Runtime: O(1)
+func (c *Cache[K, V]) Remove(key K) (value V, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.items[key]
+	if !ok {
+		return value, false
+	}
+	ent, _ := c.order.Remove(e)
+	delete(c.items, key)
+	c.bytes -= ent.size
+	return ent.value, true
+}
+
+// Len is the number of entries in the cache
+//
+// Runtime: O(1)
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// Bytes is the total size of the entries in the cache as measured by the
+// function given to WithMaxBytes, or 0 without one
+//
+// Runtime: O(1)
+func (c *Cache[K, V]) Bytes() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.bytes
+}
+
+// Stats returns the number of hits, misses and evictions so far
+//
+// Runtime: O(1)
+func (c *Cache[K, V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// evict removes least recently used entries until the cache is within its
+// capacity and returns them.  The caller must hold mu.
+func (c *Cache[K, V]) evict() (evicted []*entry[K, V]) {
+	for c.overCapacity() {
+		ent, err := c.order.PopTail()
+		if err != nil {
+			break
+		}
+		delete(c.items, ent.key)
+		c.bytes -= ent.size
+		c.stats.Evictions++
+		evicted = append(evicted, ent)
+	}
+	return
+}
+
+func (c *Cache[K, V]) overCapacity() bool {
+	return (c.maxEntries > 0 && len(c.items) > c.maxEntries) ||
+		(c.maxBytes > 0 && c.bytes > c.maxBytes)
+}
@@ -0,0 +1,141 @@
+package lru
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPut(t *testing.T) {
+	c := New[string, int](2)
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+	c.Put("a", 1)
+	c.Put("b", 2)
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	// b is now the least recently used
+	c.Put("c", 3)
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+	assert.Equal(t, 2, c.Len())
+	assert.Equal(t, Stats{Hits: 1, Misses: 2, Evictions: 1}, c.Stats())
+
+	c.Put("a", 10)
+	v, _ = c.Get("a")
+	assert.Equal(t, 10, v)
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestPeekDoesNotTouch(t *testing.T) {
+	c := New[string, int](2)
+	c.Put("a", 1)
+	c.Put("b", 2)
+	v, ok := c.Peek("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	_, ok = c.Peek("z")
+	assert.False(t, ok)
+	assert.Equal(t, Stats{}, c.Stats())
+
+	// a is still the least recently used
+	c.Put("c", 3)
+	_, ok = c.Peek("a")
+	assert.False(t, ok)
+}
+
+func TestRemove(t *testing.T) {
+	evicted := 0
+	c := New[string, int](2, OnEvict(func(string, int) { evicted++ }))
+	c.Put("a", 1)
+	v, ok := c.Remove("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	_, ok = c.Remove("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+	assert.Equal(t, 0, evicted, "Remove isn't an eviction")
+}
+
+func TestMaxBytes(t *testing.T) {
+	var evicted []string
+	c := New[string, string](0,
+		WithMaxBytes(10, func(key, value string) int64 { return int64(len(value)) }),
+		OnEvict(func(key, value string) { evicted = append(evicted, key) }))
+	c.Put("a", "1234")
+	c.Put("b", "1234")
+	assert.Equal(t, int64(8), c.Bytes())
+	c.Put("c", "1234")
+	assert.Equal(t, []string{"a"}, evicted)
+	assert.Equal(t, int64(8), c.Bytes())
+
+	// growing an entry evicts others to make room
+	c.Put("c", "12345678")
+	assert.Equal(t, []string{"a", "b"}, evicted)
+	assert.Equal(t, int64(8), c.Bytes())
+
+	// an entry too big on its own is evicted straight away
+	c.Put("d", "12345678901")
+	assert.Equal(t, []string{"a", "b", "c", "d"}, evicted)
+	assert.Equal(t, 0, c.Len())
+	assert.Equal(t, int64(0), c.Bytes())
+	assert.Equal(t, uint64(4), c.Stats().Evictions)
+}
+
+func TestMaxEntriesAndBytes(t *testing.T) {
+	c := New[int, int](3, WithMaxBytes(100, func(key, value int) int64 { return int64(value) }))
+	c.Put(1, 1)
+	c.Put(2, 1)
+	c.Put(3, 1)
+	c.Put(4, 1)
+	assert.Equal(t, 3, c.Len())
+	c.Put(5, 99)
+	assert.Equal(t, 2, c.Len())
+	assert.Equal(t, int64(100), c.Bytes())
+}
+
+func TestOnEvictMayUseCache(t *testing.T) {
+	var c *Cache[int, int]
+	c = New[int, int](1, OnEvict(func(key, value int) {
+		// would deadlock if called with the lock held
+		c.Peek(key)
+	}))
+	c.Put(1, 1)
+	c.Put(2, 2)
+	assert.Equal(t, uint64(1), c.Stats().Evictions)
+}
+
+func TestNoCapacityPanics(t *testing.T) {
+	assert.Panics(t, func() { New[int, int](0) })
+}
+
+func TestConcurrentUse(t *testing.T) {
+	c := New[string, int](50)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				key := strconv.Itoa((g*7 + i) % 100)
+				if v, ok := c.Get(key); ok {
+					assert.Equal(t, key, strconv.Itoa(v))
+				} else {
+					n, _ := strconv.Atoi(key)
+					c.Put(key, n)
+				}
+				if i%10 == 0 {
+					c.Remove(key)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	assert.True(t, c.Len() <= 50)
+	stats := c.Stats()
+	assert.Equal(t, uint64(8000), stats.Hits+stats.Misses)
+}
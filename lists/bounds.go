@@ -0,0 +1,156 @@
+package lists
+
+import "context"
+
+// OverflowPolicy decides what a bounded list does with an item that is pushed
+// while the list is at capacity
+type OverflowPolicy int
+
+const (
+	// Reject refuses the new item.  Every push method returns a
+	// CapacityExceededError, including PushHead and PushTail.
+	Reject OverflowPolicy = iota
+	// Block waits until an item is removed from the list.  PushHead and
+	// PushTail wait indefinitely, PushHeadWait and PushTailWait wait until
+	// their context is done and TryPushHead and TryPushTail return a
+	// CapacityExceededError instead of waiting.
+	Block
+	// Evict removes an item from the opposite end of the list to make room,
+	// so PushHead evicts the tail and PushTail evicts the head.
+	Evict
+	// Drop silently discards the new item.  No push method reports an error.
+	Drop
+)
+
+// OverflowStats counts how a bounded list has handled pushes made while it
+// was at capacity
+type OverflowStats struct {
+	// Rejected is the number of items refused by the Reject policy, or by the
+	// Block policy when a push could not wait or its context finished first
+	Rejected uint64
+	// Evicted is the number of items removed by the Evict policy
+	Evicted uint64
+	// Dropped is the number of items discarded by the Drop policy
+	Dropped uint64
+}
+
+// bounds holds the limits on adding items to a Singly or Doubly: its capacity
+// and whether it has been closed.  All fields are guarded by the lock of the
+// list that holds it.
+type bounds struct {
+	// capacity is the maximum number of items, or 0 for an unbounded list
+	capacity int
+	policy   OverflowPolicy
+	// notFull is signaled once for every item removed from the list
+	notFull *waitCond
+	stats   OverflowStats
+	// closed is set by Close, after which no items can be added
+	closed bool
+	// done is closed once the list is closed and empty
+	done chan struct{}
+	// closeCh is closed by Close, waking goroutines in MoveWait that are
+	// waiting for an item to move into the list
+	closeCh chan struct{}
+	// holdDone is set while a transaction runs, so that done isn't closed
+	// by changes that might be rolled back
+	holdDone bool
+}
+
+func newBounds(capacity int, policy OverflowPolicy) bounds {
+	if capacity < 1 {
+		panic("lists: capacity must be positive")
+	}
+	return bounds{capacity: capacity, policy: policy}
+}
+
+// full returns true if a list holding size items has no room for another
+func (b *bounds) full(size int) bool {
+	return b.capacity > 0 && size >= b.capacity
+}
+
+// admit checks that the list is open and applies the overflow policy before
+// an item is added to a list that currently holds *size items.  evict removes
+// an item from the opposite end of the list, or is nil if the caller can't
+// evict, in which case the Evict policy rejects the item.  If wait is true the
+// Block policy waits for room until ctx is done, otherwise it rejects the item
+// straight away.  Returns false if the item must not be added, with a
+// ClosedListError if the list has been closed.  The caller must hold the
+// write lock.
+func (b *bounds) admit(ctx context.Context, wait bool, size *int, evict func()) (ok bool, err error) {
+	for {
+		if b.closed {
+			return false, ClosedListError("can't add an item to a closed list")
+		}
+		if !b.full(*size) {
+			return true, nil
+		}
+		switch {
+		case b.policy == Evict && evict != nil:
+			evict()
+			b.stats.Evicted++
+		case b.policy == Drop:
+			b.stats.Dropped++
+			return false, nil
+		case b.policy == Block && wait:
+			if err = b.notFull.Wait(ctx); err != nil {
+				b.stats.Rejected++
+				return false, err
+			}
+		default:
+			b.stats.Rejected++
+			return false, CapacityExceededError("can't add an item to a full list")
+		}
+	}
+}
+
+// close marks the list as closed and wakes any goroutine waiting for room.
+// Returns a ClosedListError if the list was already closed.  The caller must
+// hold the write lock.
+func (b *bounds) close(size int) error {
+	if b.closed {
+		return ClosedListError("list is already closed")
+	}
+	b.closed = true
+	close(b.closeCh)
+	b.notFull.Broadcast()
+	b.checkDone(size)
+	return nil
+}
+
+// removed is called after an item is removed from a list that now holds size
+// items.  The caller must hold the write lock.
+func (b *bounds) removed(size int) {
+	b.notFull.Signal()
+	b.checkDone(size)
+}
+
+// checkReplace checks that a list can have its items replaced by n items.
+// The caller must hold the write lock.
+func (b *bounds) checkReplace(n int) error {
+	if b.closed {
+		return ClosedListError("can't add an item to a closed list")
+	}
+	if b.capacity > 0 && n > b.capacity {
+		return CapacityExceededError("can't replace the items of a list with more than it can hold")
+	}
+	return nil
+}
+
+// removedMany is called after any number of items are removed from a list at
+// once, leaving size items.  The caller must hold the write lock.
+func (b *bounds) removedMany(size int) {
+	b.notFull.Broadcast()
+	b.checkDone(size)
+}
+
+// checkDone closes done if the list is closed and holds no items.  The caller
+// must hold the write lock.
+func (b *bounds) checkDone(size int) {
+	if b.closed && size == 0 && !b.holdDone {
+		select {
+		case <-b.done:
+		default:
+			close(b.done)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package lists
+
+import (
+	"context"
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// boundedList is the part of Singly and Doubly exercised by BoundsTestSuite
+type boundedList interface {
+	List[int]
+	TryPushHead(data int) error
+	TryPushTail(data int) error
+	PushHeadWait(ctx context.Context, data int) error
+	PushTailWait(ctx context.Context, data int) error
+	InsertAt(i int, data int) error
+	Capacity() int
+	OverflowStats() OverflowStats
+	Values() iter.Seq[int]
+}
+
+type BoundsTestSuite struct {
+	suite.Suite
+	newList func(capacity int, policy OverflowPolicy) boundedList
+}
+
+func TestBoundsTestSuite(t *testing.T) {
+	suite.Run(t, &BoundsTestSuite{newList: func(capacity int, policy OverflowPolicy) boundedList {
+		return NewBoundedSingly[int](capacity, policy)
+	}})
+	suite.Run(t, &BoundsTestSuite{newList: func(capacity int, policy OverflowPolicy) boundedList {
+		return NewBoundedDoubly[int](capacity, policy)
+	}})
+}
+
+func (suite *BoundsTestSuite) items(list boundedList) (items []int) {
+	for item := range list.Values() {
+		items = append(items, item)
+	}
+	return
+}
+
+func (suite *BoundsTestSuite) TestCapacity() {
+	list := suite.newList(3, Reject)
+	assert.Equal(suite.T(), 3, list.Capacity())
+	assert.Panics(suite.T(), func() { suite.newList(0, Reject) })
+	assert.Equal(suite.T(), 0, NewSinglyOf[int]().Capacity(), "unbounded list should have no capacity")
+	assert.Equal(suite.T(), 0, NewDoublyOf[int]().Capacity(), "unbounded list should have no capacity")
+}
+
+func (suite *BoundsTestSuite) TestReject() {
+	list := suite.newList(3, Reject)
+	for i := 0; i < 3; i++ {
+		assert.NoError(suite.T(), list.TryPushTail(i))
+	}
+	assert.Exactly(suite.T(), CapacityExceededError("can't add an item to a full list"), list.TryPushTail(3))
+	assert.IsType(suite.T(), CapacityExceededError(""), list.TryPushHead(3))
+	assert.IsType(suite.T(), CapacityExceededError(""), list.PushHeadWait(context.Background(), 3))
+	assert.IsType(suite.T(), CapacityExceededError(""), list.InsertAt(1, 3))
+	assert.IsType(suite.T(), CapacityExceededError(""), list.PushTail(3), "PushTail should report the rejection")
+	assert.IsType(suite.T(), CapacityExceededError(""), list.PushHead(3), "PushHead should report the rejection")
+	assert.Equal(suite.T(), []int{0, 1, 2}, suite.items(list))
+	assert.Equal(suite.T(), OverflowStats{Rejected: 6}, list.OverflowStats())
+	list.PopHead()
+	assert.NoError(suite.T(), list.TryPushHead(4), "list should have room after PopHead")
+	assert.Equal(suite.T(), []int{4, 1, 2}, suite.items(list))
+}
+
+func (suite *BoundsTestSuite) TestDrop() {
+	list := suite.newList(2, Drop)
+	list.PushTail(0)
+	list.PushTail(1)
+	assert.NoError(suite.T(), list.TryPushTail(2))
+	assert.NoError(suite.T(), list.TryPushHead(3))
+	assert.NoError(suite.T(), list.PushTailWait(context.Background(), 4))
+	assert.NoError(suite.T(), list.InsertAt(1, 5))
+	assert.NoError(suite.T(), list.PushHead(6))
+	assert.Equal(suite.T(), []int{0, 1}, suite.items(list))
+	assert.Equal(suite.T(), OverflowStats{Dropped: 5}, list.OverflowStats())
+}
+
+func (suite *BoundsTestSuite) TestEvict() {
+	list := suite.newList(3, Evict)
+	for i := 0; i < 5; i++ {
+		list.PushTail(i)
+	}
+	assert.Equal(suite.T(), []int{2, 3, 4}, suite.items(list), "PushTail should evict the head")
+	assert.NoError(suite.T(), list.TryPushHead(5))
+	assert.Equal(suite.T(), []int{5, 2, 3}, suite.items(list), "PushHead should evict the tail")
+	assert.IsType(suite.T(), CapacityExceededError(""), list.InsertAt(1, 6), "InsertAt has no opposite end to evict")
+	assert.Equal(suite.T(), 3, list.Size())
+	assert.Equal(suite.T(), OverflowStats{Evicted: 3, Rejected: 1}, list.OverflowStats())
+}
+
+func (suite *BoundsTestSuite) TestBlock() {
+	list := suite.newList(2, Block)
+	list.PushTail(0)
+	list.PushTail(1)
+	assert.IsType(suite.T(), CapacityExceededError(""), list.TryPushTail(2), "TryPushTail should not wait")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(suite.T(), context.DeadlineExceeded, list.PushTailWait(ctx, 2))
+	assert.Equal(suite.T(), OverflowStats{Rejected: 2}, list.OverflowStats())
+
+	done := make(chan error)
+	go func() {
+		done <- list.PushTailWait(context.Background(), 2)
+	}()
+	go func() {
+		list.PushHead(3)
+		done <- nil
+	}()
+	select {
+	case <-done:
+		suite.T().Fatal("push should wait for room")
+	case <-time.After(10 * time.Millisecond):
+	}
+	list.PopHead()
+	assert.NoError(suite.T(), <-done)
+	list.PopTail()
+	assert.NoError(suite.T(), <-done)
+	assert.Equal(suite.T(), 2, list.Size())
+}
@@ -0,0 +1,147 @@
+package lists
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// closableList is the part of Singly and Doubly exercised by CloseTestSuite
+type closableList interface {
+	List[int]
+	TryPushHead(data int) error
+	TryPushTail(data int) error
+	PushTailWait(ctx context.Context, data int) error
+	PopHeadWait(ctx context.Context) (int, error)
+	PopTailWait(ctx context.Context) (int, error)
+	InsertAt(i int, data int) error
+	Close() error
+	IsClosed() bool
+	Done() <-chan struct{}
+}
+
+type CloseTestSuite struct {
+	suite.Suite
+	newList func(capacity int) closableList
+}
+
+func TestCloseTestSuite(t *testing.T) {
+	suite.Run(t, &CloseTestSuite{newList: func(capacity int) closableList {
+		if capacity > 0 {
+			return NewBoundedSingly[int](capacity, Block)
+		}
+		return NewSinglyOf[int]()
+	}})
+	suite.Run(t, &CloseTestSuite{newList: func(capacity int) closableList {
+		if capacity > 0 {
+			return NewBoundedDoubly[int](capacity, Block)
+		}
+		return NewDoublyOf[int]()
+	}})
+}
+
+func (suite *CloseTestSuite) isDone(list closableList) bool {
+	select {
+	case <-list.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+func (suite *CloseTestSuite) TestClose() {
+	list := suite.newList(0)
+	assert.False(suite.T(), list.IsClosed())
+	list.PushTail(1)
+	list.PushTail(2)
+	assert.NoError(suite.T(), list.Close())
+	assert.True(suite.T(), list.IsClosed())
+	assert.Exactly(suite.T(), ClosedListError("list is already closed"), list.Close())
+
+	assert.Exactly(suite.T(), ClosedListError("can't add an item to a closed list"), list.TryPushTail(3))
+	assert.IsType(suite.T(), ClosedListError(""), list.TryPushHead(3))
+	assert.IsType(suite.T(), ClosedListError(""), list.InsertAt(0, 3))
+	assert.IsType(suite.T(), ClosedListError(""), list.PushHead(3))
+	assert.IsType(suite.T(), ClosedListError(""), list.PushTail(3))
+	assert.Equal(suite.T(), 2, list.Size())
+
+	assert.False(suite.T(), suite.isDone(list), "Done should wait until the list is empty")
+	item, err := list.PopHead()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, item)
+	item, err = list.PopTailWait(context.Background())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, item)
+	assert.True(suite.T(), suite.isDone(list), "Done should fire once the list is closed and empty")
+
+	wanted := DrainedListError("can't remove an item from a closed and empty list")
+	_, err = list.PopHead()
+	assert.Exactly(suite.T(), wanted, err)
+	_, err = list.PopTail()
+	assert.Exactly(suite.T(), wanted, err)
+	_, err = list.PopHeadWait(context.Background())
+	assert.Exactly(suite.T(), wanted, err)
+}
+
+func (suite *CloseTestSuite) TestCloseEmpty() {
+	list := suite.newList(0)
+	assert.NoError(suite.T(), list.Close())
+	assert.True(suite.T(), suite.isDone(list), "Done should fire straight away for an empty list")
+	_, err := list.PopTail()
+	assert.IsType(suite.T(), DrainedListError(""), err)
+}
+
+func (suite *CloseTestSuite) TestCloseWakesPoppers() {
+	list := suite.newList(0)
+	errs := make(chan error, 2)
+	go func() {
+		_, err := list.PopHeadWait(context.Background())
+		errs <- err
+	}()
+	go func() {
+		_, err := list.PopTailWait(context.Background())
+		errs <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	list.Close()
+	assert.IsType(suite.T(), DrainedListError(""), <-errs)
+	assert.IsType(suite.T(), DrainedListError(""), <-errs)
+}
+
+func (suite *CloseTestSuite) TestCloseWakesPushers() {
+	list := suite.newList(1)
+	list.PushTail(1)
+	errs := make(chan error)
+	go func() {
+		errs <- list.PushTailWait(context.Background(), 2)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	list.Close()
+	assert.IsType(suite.T(), ClosedListError(""), <-errs)
+	assert.Equal(suite.T(), 1, list.Size())
+}
+
+func (suite *CloseTestSuite) TestPipeline() {
+	list := suite.newList(4)
+	go func() {
+		for i := 0; i < 100; i++ {
+			list.PushTail(i)
+		}
+		list.Close()
+	}()
+	next := 0
+	for {
+		item, err := list.PopHeadWait(context.Background())
+		if err != nil {
+			assert.IsType(suite.T(), DrainedListError(""), err)
+			break
+		}
+		assert.Equal(suite.T(), next, item, "items should arrive in order")
+		next++
+	}
+	assert.Equal(suite.T(), 100, next)
+	<-list.Done()
+}
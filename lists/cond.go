@@ -0,0 +1,124 @@
+package lists
+
+import (
+	"context"
+	"sync"
+)
+
+// waitCond is a condition variable whose Wait can be abandoned when a
+// context is done.  Waiters are woken in the order they started waiting.
+// Like sync.Cond, every method must be called while holding the lock the
+// condition is tied to.
+//
+// A woken goroutine has to reacquire the lock before it can act, so a
+// goroutine that arrives in the meantime could get there first.  Ready and
+// WaitTurn stop that from happening: a caller that hasn't waited yet lets
+// every goroutine already waiting, or woken but not yet running, go first.
+type waitCond struct {
+	l       sync.Locker
+	waiters []waiter
+	// woken is the number of goroutines that have been signaled but haven't
+	// reacquired the lock yet
+	woken int
+	// tickets hands out places in line, starting at 1 so that a ticket of 0
+	// means a caller that hasn't waited yet
+	tickets uint64
+}
+
+type waiter struct {
+	ch     chan struct{}
+	ticket uint64
+}
+
+func newWaitCond(l sync.Locker) *waitCond {
+	return &waitCond{l: l}
+}
+
+// Wait releases the lock, waits until it is signaled or ctx is done and then
+// reacquires the lock.  Returns ctx.Err() if ctx finished first.
+func (c *waitCond) Wait(ctx context.Context) error {
+	var ticket uint64
+	return c.WaitTurn(ctx, &ticket)
+}
+
+// Ready reports whether a caller holding ticket may take one of avail items
+// without waiting.  A caller that hasn't waited yet, with a ticket of 0, may
+// only take an item if nobody is waiting and there are more items than
+// goroutines that have been woken to take them.  A caller that has waited
+// already has had its turn and only needs an item to be there.
+func (c *waitCond) Ready(ticket uint64, avail int) bool {
+	if ticket != 0 {
+		return avail > 0
+	}
+	return len(c.waiters) == 0 && avail > c.woken
+}
+
+// WaitTurn is Wait for a caller that may need to wait more than once.
+// *ticket is 0 the first time and is then set to the caller's place in line,
+// so that waiting again puts it ahead of goroutines that started waiting
+// after it did.
+func (c *waitCond) WaitTurn(ctx context.Context, ticket *uint64) error {
+	return c.WaitTurnOr(ctx, ticket, nil)
+}
+
+// WaitTurnOr is WaitTurn but also stops waiting once abort is closed, in
+// which case it reacquires the lock and returns nil so that the caller can
+// check why.  A nil abort never stops the wait.
+func (c *waitCond) WaitTurnOr(ctx context.Context, ticket *uint64, abort <-chan struct{}) (err error) {
+	if *ticket == 0 {
+		c.tickets++
+		*ticket = c.tickets
+	}
+	w := waiter{ch: make(chan struct{}), ticket: *ticket}
+	i := len(c.waiters)
+	for i > 0 && c.waiters[i-1].ticket > w.ticket {
+		i--
+	}
+	c.waiters = append(c.waiters, waiter{})
+	copy(c.waiters[i+1:], c.waiters[i:])
+	c.waiters[i] = w
+	c.l.Unlock()
+	select {
+	case <-w.ch:
+		c.l.Lock()
+		c.woken--
+		return nil
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-abort:
+	}
+	c.l.Lock()
+	for i, waiter := range c.waiters {
+		if waiter.ch == w.ch {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			return err
+		}
+	}
+	// w was signaled after ctx finished or abort was closed, so pass the
+	// wake-up on to the next waiter instead of losing it
+	c.woken--
+	c.Signal()
+	return err
+}
+
+// Signal wakes the longest waiting goroutine, if there is one
+func (c *waitCond) Signal() {
+	c.SignalN(1)
+}
+
+// SignalN wakes the n longest waiting goroutines, or every goroutine if
+// fewer are waiting
+func (c *waitCond) SignalN(n int) {
+	n = min(n, len(c.waiters))
+	for i := range n {
+		close(c.waiters[i].ch)
+		c.waiters[i] = waiter{}
+	}
+	c.waiters = c.waiters[n:]
+	c.woken += n
+}
+
+// Broadcast wakes every waiting goroutine
+func (c *waitCond) Broadcast() {
+	c.SignalN(len(c.waiters))
+}
@@ -0,0 +1,136 @@
+package lists
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// waitForWaiters spins until c has n waiters
+func waitForWaiters(l sync.Locker, c *waitCond, n int) {
+	for {
+		l.Lock()
+		waiting := len(c.waiters)
+		l.Unlock()
+		if waiting >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWaitCondFIFO(t *testing.T) {
+	var mu sync.Mutex
+	c := newWaitCond(&mu)
+	woken := make(chan int, 3)
+	for i := 0; i < 3; i++ {
+		go func(i int) {
+			mu.Lock()
+			defer mu.Unlock()
+			assert.NoError(t, c.Wait(context.Background()))
+			woken <- i
+		}(i)
+		waitForWaiters(&mu, c, i+1)
+	}
+	for i := 0; i < 3; i++ {
+		mu.Lock()
+		c.Signal()
+		mu.Unlock()
+		assert.Equal(t, i, <-woken, "waiters should be woken in the order they started waiting")
+	}
+}
+
+func TestWaitCondCancel(t *testing.T) {
+	var mu sync.Mutex
+	c := newWaitCond(&mu)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		mu.Lock()
+		defer mu.Unlock()
+		done <- c.Wait(ctx)
+	}()
+	waitForWaiters(&mu, c, 1)
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+	mu.Lock()
+	assert.Empty(t, c.waiters, "cancelled waiter should be forgotten")
+	mu.Unlock()
+}
+
+func TestWaitCondBroadcast(t *testing.T) {
+	var mu sync.Mutex
+	c := newWaitCond(&mu)
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			mu.Lock()
+			defer mu.Unlock()
+			assert.NoError(t, c.Wait(context.Background()))
+		}()
+	}
+	waitForWaiters(&mu, c, 3)
+	mu.Lock()
+	c.Broadcast()
+	mu.Unlock()
+	wg.Wait()
+}
+
+func TestWaitCondReady(t *testing.T) {
+	var mu sync.Mutex
+	c := newWaitCond(&mu)
+	assert.False(t, c.Ready(0, 0))
+	assert.True(t, c.Ready(0, 1))
+	go func() {
+		mu.Lock()
+		defer mu.Unlock()
+		assert.NoError(t, c.Wait(context.Background()))
+	}()
+	waitForWaiters(&mu, c, 1)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.False(t, c.Ready(0, 1), "a new caller should queue behind a waiting one")
+	c.Signal()
+	assert.False(t, c.Ready(0, 1), "the item is meant for the woken goroutine")
+	assert.True(t, c.Ready(0, 2))
+	assert.True(t, c.Ready(1, 1), "a caller that has waited only needs an item")
+}
+
+func TestPopWaitFair(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		list := NewDoublyOf[int]()
+		first := make(chan int)
+		go func() {
+			data, err := list.PopHeadWait(context.Background())
+			assert.NoError(t, err)
+			first <- data
+		}()
+		waitForWaiters(list.rwLock, list.notEmpty, 1)
+
+		// wake the first waiter and start a second caller before the first
+		// can reacquire the lock
+		list.rwLock.Lock()
+		list.pushTail(1)
+		second := make(chan int)
+		go func() {
+			data, err := list.PopHeadWait(context.Background())
+			assert.NoError(t, err)
+			second <- data
+		}()
+		list.rwLock.Unlock()
+
+		select {
+		case data := <-first:
+			assert.Equal(t, 1, data)
+		case <-second:
+			t.Fatal("a later caller took the item meant for the goroutine that waited first")
+		}
+		list.PushTail(2)
+		assert.Equal(t, 2, <-second)
+	}
+}
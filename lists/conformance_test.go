@@ -0,0 +1,52 @@
+package lists_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/suicidejack/go-various/lists"
+	"github.com/suicidejack/go-various/lists/liststest"
+)
+
+func TestSinglyConformance(t *testing.T) {
+	liststest.RunList(t, func() lists.List[int] { return lists.NewSinglyOf[int]() })
+}
+
+func TestDoublyConformance(t *testing.T) {
+	liststest.RunList(t, func() lists.List[int] { return lists.NewDoublyOf[int]() })
+}
+
+func TestSinglyStackConformance(t *testing.T) {
+	liststest.RunStack(t, func() lists.Stack[int] { return lists.NewStack[int]() })
+}
+
+func TestLockFreeStackConformance(t *testing.T) {
+	liststest.RunStack(t, func() lists.Stack[int] { return lists.NewLockFreeStack[int]() })
+	liststest.RunStack(t, func() lists.Stack[int] { return &lists.LockFreeStack[int]{} })
+}
+
+func TestDoublyQueueConformance(t *testing.T) {
+	liststest.RunQueue(t, func() lists.Queue[int] { return lists.NewQueue[int]() })
+}
+
+func TestLockFreeQueueConformance(t *testing.T) {
+	liststest.RunQueue(t, func() lists.Queue[int] { return lists.NewLockFreeQueue[int]() })
+}
+
+func TestTwoLockQueueConformance(t *testing.T) {
+	liststest.RunQueue(t, func() lists.Queue[int] { return lists.NewTwoLockQueue[int]() })
+}
+
+func TestLockCoupledConformance(t *testing.T) {
+	liststest.RunList(t, func() lists.List[int] { return lists.NewLockCoupled[int]() })
+}
+
+func TestUnsynchronizedConformance(t *testing.T) {
+	liststest.RunSingleGoroutineList(t, func() lists.List[int] { return lists.NewSinglyOf[int](lists.WithoutLocking()) })
+	liststest.RunSingleGoroutineList(t, func() lists.List[int] { return lists.NewDoublyOf[int](lists.WithoutLocking()) })
+}
+
+func TestWithLockerConformance(t *testing.T) {
+	liststest.RunList(t, func() lists.List[int] { return lists.NewSinglyOf[int](lists.WithLocker(&sync.Mutex{})) })
+	liststest.RunList(t, func() lists.List[int] { return lists.NewDoublyOf[int](lists.WithLocker(&sync.Mutex{})) })
+}
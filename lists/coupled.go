@@ -0,0 +1,781 @@
+package lists
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// LockCoupled goroutine-safe doubly-linked list where every node has its own
+// lock.  Scans in Contains and Delete use hand-over-hand locking (lock
+// coupling): they lock the next node before releasing the current one, so
+// they hold at most three node locks at a time.  Pushes, pops and scans on
+// different parts of the list run concurrently, and a long predicate walk
+// only delays the goroutines that need the nodes it currently holds.
+//
+// It has the same methods as Doubly, including bounds, Close and the Wait
+// methods, apart from the ones that need the whole list to hold still: the
+// conditional pops (PopHeadIf, PopTailIf and SwapHead), Sort, Move, the
+// splice and transaction methods and encoding.  Locks are always acquired
+// from the head towards the tail, which keeps the list free of deadlocks.
+// The capacity, the closed state and the goroutines waiting in the Wait
+// methods are guarded by one more lock, the gate, which every method that
+// adds an item holds while it does so and which removals take briefly once
+// they have released their node locks.  Nothing waits for the gate while
+// holding a node lock, so the gate can't deadlock with them either.
+type LockCoupled[T any] struct {
+	// head and tail are sentinel nodes that are never removed
+	head *coupledNode[T]
+	tail *coupledNode[T]
+	size atomic.Int64
+	// gate guards bounds, notEmpty and admitSize.  It may be held while
+	// taking node locks, but not the other way round.
+	gate     sync.Mutex
+	notEmpty *waitCond
+	bounds   bounds
+	// admitSize is the size of the list as last seen while holding the
+	// gate, for bounds.admit.  The size can only grow while the gate is
+	// held, so it is never less than the real size.
+	admitSize int
+}
+
+type coupledNode[T any] struct {
+	mu sync.Mutex
+	// next and prev are only changed while holding mu, but are atomic so
+	// that PushTail and PopTail can read them before locking and then
+	// validate what they read
+	next atomic.Pointer[coupledNode[T]]
+	prev atomic.Pointer[coupledNode[T]]
+	// owner is the list the node was added to, or nil once it has been
+	// removed.  It stays set while MoveToHead or MoveToTail moves the node,
+	// when prev and next are briefly nil.
+	owner atomic.Pointer[LockCoupled[T]]
+	data  T
+}
+
+var _ List[interface{}] = (*LockCoupled[interface{}])(nil)
+
+// NewLockCoupled creates a new empty lock-coupled list holding items of type
+// T
+func NewLockCoupled[T any]() *LockCoupled[T] {
+	return newLockCoupled[T](bounds{})
+}
+
+// NewBoundedLockCoupled creates a new empty lock-coupled list holding at most
+// capacity items of type T.  policy decides what happens to items pushed
+// while the list is full.  Panics if capacity is less than 1.
+func NewBoundedLockCoupled[T any](capacity int, policy OverflowPolicy) *LockCoupled[T] {
+	return newLockCoupled[T](newBounds(capacity, policy))
+}
+
+func newLockCoupled[T any](b bounds) *LockCoupled[T] {
+	l := &LockCoupled[T]{head: &coupledNode[T]{}, tail: &coupledNode[T]{}}
+	l.head.next.Store(l.tail)
+	l.tail.prev.Store(l.head)
+	l.notEmpty = newWaitCond(&l.gate)
+	b.notFull = newWaitCond(&l.gate)
+	b.done = make(chan struct{})
+	b.closeCh = make(chan struct{})
+	l.bounds = b
+	return l
+}
+
+// Size of the list.  While other goroutines are modifying the list the
+// result is only a snapshot.
+//
+// Runtime: O(1)
+func (l *LockCoupled[T]) Size() int {
+	return int(l.size.Load())
+}
+
+// IsEmpty returns true if the list contains no items
+//
+// Runtime: O(1)
+func (l *LockCoupled[T]) IsEmpty() bool {
+	return l.head.next.Load() == l.tail
+}
+
+// PushHead adds data to the front of the list.  Besides the gate, only the
+// head sentinel and the first node are locked.  On a bounded list that is
+// full, the list's OverflowPolicy applies: Reject returns a
+// CapacityExceededError, Block waits for room, Evict removes the tail and
+// Drop discards the item without an error.  Returns a ClosedListError if the
+// list has been closed.
+//
+// Runtime: O(1)
+func (l *LockCoupled[T]) PushHead(data T) error {
+	_, err := l.push(context.Background(), true, data, true)
+	return err
+}
+
+// PushTail adds data to the back of the list.  Besides the gate, only the
+// last node and the tail sentinel are locked.  On a bounded list that is
+// full, the list's OverflowPolicy applies: Reject returns a
+// CapacityExceededError, Block waits for room, Evict removes the head and
+// Drop discards the item without an error.  Returns a ClosedListError if the
+// list has been closed.
+//
+// Runtime: O(1), retrying if another goroutine changes the last node first
+func (l *LockCoupled[T]) PushTail(data T) error {
+	_, err := l.push(context.Background(), true, data, false)
+	return err
+}
+
+// TryPushHead adds data to the front of the list without waiting.  Returns a
+// CapacityExceededError if the list is full and its policy is Reject or
+// Block, or a ClosedListError if the list has been closed.
+//
+// Runtime: O(1)
+func (l *LockCoupled[T]) TryPushHead(data T) error {
+	_, err := l.push(context.Background(), false, data, true)
+	return err
+}
+
+// TryPushTail adds data to the back of the list without waiting, like
+// TryPushHead.
+//
+// Runtime: O(1), retrying if another goroutine changes the last node first
+func (l *LockCoupled[T]) TryPushTail(data T) error {
+	_, err := l.push(context.Background(), false, data, false)
+	return err
+}
+
+// PushHeadWait adds data to the front of the list.  If the list is full and
+// its policy is Block, it waits for room and returns ctx.Err() if ctx is
+// done first.  Returns a CapacityExceededError if the list is full and its
+// policy is Reject, or a ClosedListError if the list has been closed, even
+// while waiting.
+//
+// Runtime: O(1)
+func (l *LockCoupled[T]) PushHeadWait(ctx context.Context, data T) error {
+	_, err := l.push(ctx, true, data, true)
+	return err
+}
+
+// PushTailWait adds data to the back of the list, waiting for room like
+// PushHeadWait.
+//
+// Runtime: O(1), retrying if another goroutine changes the last node first
+func (l *LockCoupled[T]) PushTailWait(ctx context.Context, data T) error {
+	_, err := l.push(ctx, true, data, false)
+	return err
+}
+
+// Capacity returns the maximum number of items the list can hold, or 0 if
+// the list is unbounded
+//
+// Runtime: O(1)
+func (l *LockCoupled[T]) Capacity() int {
+	return l.bounds.capacity
+}
+
+// OverflowStats returns how many items the list's OverflowPolicy has
+// rejected, evicted or dropped so far
+//
+// Runtime: O(1)
+func (l *LockCoupled[T]) OverflowStats() OverflowStats {
+	l.gate.Lock()
+	defer l.gate.Unlock()
+	return l.bounds.stats
+}
+
+// PopHead removes data from the front of the list.  Returns an
+// EmptyListError if there are no items in the list, or a DrainedListError if
+// the list has also been closed.  Only the head sentinel and the first two
+// nodes are locked, and then the gate.
+//
+// Runtime: O(1)
+func (l *LockCoupled[T]) PopHead() (data T, err error) {
+	data, ok := l.popHead()
+	if !ok {
+		return data, l.emptyError()
+	}
+	l.removed(1)
+	return data, nil
+}
+
+// PopTail removes data from the back of the list.  Returns an EmptyListError
+// if there are no items in the list, or a DrainedListError if the list has
+// also been closed.  Only the last two nodes and the tail sentinel are
+// locked, and then the gate.
+//
+// Runtime: O(1), retrying if another goroutine changes the last nodes first
+func (l *LockCoupled[T]) PopTail() (data T, err error) {
+	data, ok := l.popTail()
+	if !ok {
+		return data, l.emptyError()
+	}
+	l.removed(1)
+	return data, nil
+}
+
+// PopHeadWait removes data from the front of the list, waiting for an item
+// to be added if the list is empty.  Goroutines waiting on the same list are
+// handed items in the order they started waiting, whichever end they pop
+// from.  PopHead, PopTail and other methods that don't wait can still take
+// an item meant for a waiting goroutine, which then keeps its place at the
+// front of the line.  Returns ctx.Err() if ctx is cancelled or its deadline
+// passes before an item is available, or a DrainedListError once the list
+// has been closed and emptied.
+//
+// Runtime: O(1)
+func (l *LockCoupled[T]) PopHeadWait(ctx context.Context) (data T, err error) {
+	return l.popWait(ctx, l.popHead)
+}
+
+// PopTailWait removes data from the back of the list, waiting for an item to
+// be added if the list is empty.  Waiting goroutines are handed items in
+// the order they started waiting, as for PopHeadWait.  Returns ctx.Err() if
+// ctx is cancelled or its deadline passes before an item is available, or a
+// DrainedListError once the list has been closed and emptied.
+//
+// Runtime: O(1)
+func (l *LockCoupled[T]) PopTailWait(ctx context.Context) (data T, err error) {
+	return l.popWait(ctx, l.popTail)
+}
+
+// PeekHead returns the data at the front of the list without removing it.
+// Returns an EmptyListError if there are no items in the list.  Only the head
+// sentinel and the first node are locked.
+//
+// Runtime: O(1)
+func (l *LockCoupled[T]) PeekHead() (data T, err error) {
+	l.head.mu.Lock()
+	node := l.head.next.Load()
+	node.mu.Lock()
+	l.head.mu.Unlock()
+	defer node.mu.Unlock()
+	if node == l.tail {
+		return data, EmptyListError("can't peek at an empty list")
+	}
+	return node.data, nil
+}
+
+// PeekTail returns the data at the back of the list without removing it.
+// Returns an EmptyListError if there are no items in the list.  Only the last
+// node and the tail sentinel are locked.
+//
+// Runtime: O(1), retrying if another goroutine changes the last node first
+func (l *LockCoupled[T]) PeekTail() (data T, err error) {
+	for {
+		node := l.tail.prev.Load()
+		if node == l.head {
+			return data, EmptyListError("can't peek at an empty list")
+		}
+		node.mu.Lock()
+		// node was read without a lock, so check that it is still the
+		// last node
+		if node.next.Load() == l.tail {
+			data = node.data
+			node.mu.Unlock()
+			return data, nil
+		}
+		node.mu.Unlock()
+	}
+}
+
+// Close stops any more items from being added to the list: every push
+// returns a ClosedListError from then on.  Items already in the list can
+// still be removed, and once they have all gone every pop returns a
+// DrainedListError.  Goroutines waiting in PopHeadWait or PopTailWait on an
+// empty list, or waiting for room in PushHeadWait or PushTailWait, are woken.
+// Returns a ClosedListError if the list was already closed.
+//
+// Runtime: O(1)
+func (l *LockCoupled[T]) Close() error {
+	l.gate.Lock()
+	defer l.gate.Unlock()
+	if err := l.bounds.close(l.Size()); err != nil {
+		return err
+	}
+	l.notEmpty.Broadcast()
+	return nil
+}
+
+// IsClosed returns true if Close has been called
+//
+// Runtime: O(1)
+func (l *LockCoupled[T]) IsClosed() bool {
+	l.gate.Lock()
+	defer l.gate.Unlock()
+	return l.bounds.closed
+}
+
+// Done returns a channel that is closed once the list has been closed and
+// every item has been removed from it
+//
+// Runtime: O(1)
+func (l *LockCoupled[T]) Done() <-chan struct{} {
+	return l.bounds.done
+}
+
+// Get returns the data at index i, counting from 0 at the head of the list.
+// Returns an IndexOutOfRangeError if i is not in [0, Size()).  Moves from
+// the head of the list to i using hand-over-hand locking.
+//
+// Runtime: O(n)
+func (l *LockCoupled[T]) Get(i int) (data T, err error) {
+	pred, node, err := l.lockAt(i, false)
+	if err != nil {
+		return
+	}
+	data = node.data
+	node.mu.Unlock()
+	pred.mu.Unlock()
+	return data, nil
+}
+
+// Set replaces the data at index i.  Returns an IndexOutOfRangeError if i is
+// not in [0, Size()).  Moves from the head of the list to i using
+// hand-over-hand locking.
+//
+// Runtime: O(n)
+func (l *LockCoupled[T]) Set(i int, data T) error {
+	pred, node, err := l.lockAt(i, false)
+	if err != nil {
+		return err
+	}
+	node.data = data
+	node.mu.Unlock()
+	pred.mu.Unlock()
+	return nil
+}
+
+// InsertAt adds data at index i, moving the item previously at i and every
+// item after it one position towards the tail.  i may equal Size() to add
+// data to the back of the list.  Returns an IndexOutOfRangeError if i is not
+// in [0, Size()].  On a bounded list that is full, the Drop policy discards
+// data and every other policy returns a CapacityExceededError.  Moves from
+// the head of the list to i using hand-over-hand locking while holding the
+// gate.
+//
+// Runtime: O(n)
+func (l *LockCoupled[T]) InsertAt(i int, data T) error {
+	l.gate.Lock()
+	defer l.gate.Unlock()
+	pred, succ, err := l.lockAt(i, true)
+	if err != nil {
+		return err
+	}
+	defer pred.mu.Unlock()
+	defer succ.mu.Unlock()
+	if ok, err := l.admit(context.Background(), false, nil); !ok {
+		return err
+	}
+	l.link(&coupledNode[T]{data: data}, pred, succ)
+	l.notEmpty.Signal()
+	return nil
+}
+
+// RemoveAt removes the data at index i.  Returns an IndexOutOfRangeError if i
+// is not in [0, Size()).  Moves from the head of the list to i using
+// hand-over-hand locking.
+//
+// Runtime: O(n)
+func (l *LockCoupled[T]) RemoveAt(i int) (data T, err error) {
+	pred, node, err := l.lockAt(i, false)
+	if err != nil {
+		return
+	}
+	succ := node.next.Load()
+	succ.mu.Lock()
+	data = l.remove(node, pred, succ)
+	succ.mu.Unlock()
+	node.mu.Unlock()
+	pred.mu.Unlock()
+	l.removed(1)
+	return data, nil
+}
+
+// All returns an iterator over the index and data of every item from the
+// head of the list to the tail.  It moves along the list using hand-over-hand
+// locking, and the lock of the current item's node is held while the loop
+// body runs, so other goroutines can work on the rest of the list but the
+// body must not call methods on the list; use Enumerate if it needs to.
+//
+// Runtime: O(n)
+func (l *LockCoupled[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		l.walk(yield)
+	}
+}
+
+// Backward returns an iterator over the index and data of every item from the
+// tail of the list to the head, like All in reverse.  Locks can only be taken
+// from the head towards the tail, so it works from a snapshot taken like
+// Enumerate's, and the loop body may call methods on the list.
+//
+// Runtime: O(n), plus O(n) memory for the snapshot
+func (l *LockCoupled[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		items := l.snapshot()
+		for i := len(items) - 1; i >= 0; i-- {
+			if !yield(i, items[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over the data of every item from the head of the
+// list to the tail.  It locks nodes like All.
+//
+// Runtime: O(n)
+func (l *LockCoupled[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		l.walk(func(_ int, data T) bool {
+			return yield(data)
+		})
+	}
+}
+
+// Enumerate returns an iterator over the index and data of every item from
+// the head of the list to the tail.  Each iteration works from a snapshot of
+// the list taken when the loop starts, so no lock is held while the loop
+// body runs and the body may modify the list.  The snapshot is taken
+// hand-over-hand rather than all at once, so it may include changes made by
+// other goroutines to the part of the list it hadn't reached yet.
+//
+// Runtime: O(n), plus O(n) memory for the snapshot
+func (l *LockCoupled[T]) Enumerate() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, data := range l.snapshot() {
+			if !yield(i, data) {
+				return
+			}
+		}
+	}
+}
+
+// Contains returns true if list contains any data where the comparison
+// function returns true.  Moves from the head of the list to the tail using
+// hand-over-hand locking, so only the nodes around the one being compared are
+// locked.
+//
+// Runtime: O(n)
+func (l *LockCoupled[T]) Contains(comparison func(data T) (exists bool)) bool {
+	pred := l.head
+	pred.mu.Lock()
+	for {
+		node := pred.next.Load()
+		node.mu.Lock()
+		pred.mu.Unlock()
+		if node == l.tail {
+			node.mu.Unlock()
+			return false
+		}
+		if comparison(node.data) {
+			node.mu.Unlock()
+			return true
+		}
+		pred = node
+	}
+}
+
+// Delete numItems data in the list based on the provided comparison function.
+// Moves from the head of list to the tail using hand-over-hand locking, so
+// only the nodes around the one being compared are locked and other
+// goroutines can push, pop and scan elsewhere in the list at the same time.
+// If the comparison function returns true for any item in the list then that
+// item is deleted.  Returns the number of items that were deleted.  If
+// numItems is <= 0 then all data in the list is scanned.
+//
+// Runtime: O(n)
+func (l *LockCoupled[T]) Delete(numItems int, comparison func(data T) (shouldDelete bool)) (numDeleted int) {
+	pred := l.head
+	pred.mu.Lock()
+	node := pred.next.Load()
+	node.mu.Lock()
+	for node != l.tail {
+		if comparison(node.data) {
+			succ := node.next.Load()
+			succ.mu.Lock()
+			l.remove(node, pred, succ)
+			node.mu.Unlock()
+			node = succ
+			numDeleted++
+			if numItems == numDeleted {
+				break
+			}
+			continue
+		}
+		pred.mu.Unlock()
+		pred = node
+		node = node.next.Load()
+		node.mu.Lock()
+	}
+	node.mu.Unlock()
+	pred.mu.Unlock()
+	l.removed(numDeleted)
+	return
+}
+
+// push adds data to the front or back of the list after applying the
+// overflow policy and returns its node, or nil if it wasn't added.  See
+// bounds.admit for how ctx and wait are used.
+func (l *LockCoupled[T]) push(ctx context.Context, wait bool, data T, atHead bool) (node *coupledNode[T], err error) {
+	l.gate.Lock()
+	defer l.gate.Unlock()
+	ok, err := l.admit(ctx, wait, func() {
+		if atHead {
+			l.popTail()
+		} else {
+			l.popHead()
+		}
+	})
+	if !ok {
+		return nil, err
+	}
+	node = &coupledNode[T]{data: data}
+	if atHead {
+		l.pushHead(node)
+	} else {
+		l.pushTail(node)
+	}
+	l.notEmpty.Signal()
+	return node, nil
+}
+
+// admit applies bounds.admit to the list, refreshing admitSize first and
+// after every eviction.  The caller must hold the gate.
+func (l *LockCoupled[T]) admit(ctx context.Context, wait bool, evict func()) (ok bool, err error) {
+	l.admitSize = l.Size()
+	if evict != nil {
+		pop := evict
+		evict = func() {
+			pop()
+			l.admitSize = l.Size()
+		}
+	}
+	return l.bounds.admit(ctx, wait, &l.admitSize, evict)
+}
+
+// pushHead links node at the front of the list.  The caller must hold the
+// gate.
+func (l *LockCoupled[T]) pushHead(node *coupledNode[T]) {
+	l.head.mu.Lock()
+	defer l.head.mu.Unlock()
+	succ := l.head.next.Load()
+	succ.mu.Lock()
+	defer succ.mu.Unlock()
+	l.link(node, l.head, succ)
+}
+
+// pushTail links node at the back of the list.  The caller must hold the
+// gate.
+func (l *LockCoupled[T]) pushTail(node *coupledNode[T]) {
+	for {
+		pred := l.tail.prev.Load()
+		pred.mu.Lock()
+		l.tail.mu.Lock()
+		if l.tail.prev.Load() == pred {
+			l.link(node, pred, l.tail)
+			l.tail.mu.Unlock()
+			pred.mu.Unlock()
+			return
+		}
+		l.tail.mu.Unlock()
+		pred.mu.Unlock()
+	}
+}
+
+// popHead removes the first node and returns its data, or returns false if
+// the list is empty.  It doesn't take the gate, so the caller must call
+// removed afterwards unless it already holds the gate.
+func (l *LockCoupled[T]) popHead() (data T, ok bool) {
+	l.head.mu.Lock()
+	defer l.head.mu.Unlock()
+	node := l.head.next.Load()
+	if node == l.tail {
+		return data, false
+	}
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	succ := node.next.Load()
+	succ.mu.Lock()
+	defer succ.mu.Unlock()
+	return l.remove(node, l.head, succ), true
+}
+
+// popTail removes the last node and returns its data, or returns false if
+// the list is empty.  Like popHead it doesn't take the gate.
+func (l *LockCoupled[T]) popTail() (data T, ok bool) {
+	for {
+		node := l.tail.prev.Load()
+		if node == l.head {
+			return data, false
+		}
+		pred := node.prev.Load()
+		if pred == nil {
+			// node was removed after it was read, so start again
+			continue
+		}
+		pred.mu.Lock()
+		// pred may have been moved behind node since it was read, so
+		// don't wait for node's lock out of order
+		if !node.mu.TryLock() {
+			pred.mu.Unlock()
+			runtime.Gosched()
+			continue
+		}
+		l.tail.mu.Lock()
+		// pred and node were read without locks, so check that they are
+		// still the last two nodes
+		valid := pred.next.Load() == node && node.next.Load() == l.tail
+		if valid {
+			data = l.remove(node, pred, l.tail)
+		}
+		l.tail.mu.Unlock()
+		node.mu.Unlock()
+		pred.mu.Unlock()
+		if valid {
+			return data, true
+		}
+	}
+}
+
+// popWait waits until the caller may take an item, ctx is done or the list
+// is closed while empty, and then removes an item with pop.  Callers that
+// are already waiting go first.  If a goroutine that doesn't wait takes the
+// item first, the caller waits again without losing its place.
+func (l *LockCoupled[T]) popWait(ctx context.Context, pop func() (T, bool)) (data T, err error) {
+	l.gate.Lock()
+	defer l.gate.Unlock()
+	var ticket uint64
+	for {
+		if l.bounds.closed || l.notEmpty.Ready(ticket, l.Size()) {
+			if data, ok := pop(); ok {
+				l.admitSize = l.Size()
+				l.bounds.removed(l.admitSize)
+				return data, nil
+			}
+			if l.bounds.closed {
+				return data, DrainedListError("can't remove an item from a closed and empty list")
+			}
+		}
+		if err = l.notEmpty.WaitTurn(ctx, &ticket); err != nil {
+			return
+		}
+	}
+}
+
+// removed is called after n items are removed from the list by a goroutine
+// that doesn't hold the gate or any node lock, to wake goroutines waiting
+// for room and close Done if the list has been closed and emptied
+func (l *LockCoupled[T]) removed(n int) {
+	if n == 0 {
+		return
+	}
+	l.gate.Lock()
+	defer l.gate.Unlock()
+	l.admitSize = l.Size()
+	if n == 1 {
+		l.bounds.removed(l.admitSize)
+	} else {
+		l.bounds.removedMany(l.admitSize)
+	}
+}
+
+// emptyError returns the error for popping from the list while it is empty
+func (l *LockCoupled[T]) emptyError() error {
+	l.gate.Lock()
+	defer l.gate.Unlock()
+	if l.bounds.closed {
+		return DrainedListError("can't remove an item from a closed and empty list")
+	}
+	return EmptyListError("can't remove an item from an empty list")
+}
+
+// lockAt moves from the head of the list using hand-over-hand locking and
+// returns the node at index i and the node before it, both locked.  If
+// allowEnd is true, i may equal the size of the list, in which case node is
+// the tail sentinel.  Returns an IndexOutOfRangeError, with no locks held, if
+// i is out of range.
+func (l *LockCoupled[T]) lockAt(i int, allowEnd bool) (pred, node *coupledNode[T], err error) {
+	if i < 0 {
+		return nil, nil, l.indexError(i)
+	}
+	pred = l.head
+	pred.mu.Lock()
+	node = pred.next.Load()
+	node.mu.Lock()
+	j := 0
+	for ; j < i && node != l.tail; j++ {
+		pred.mu.Unlock()
+		pred = node
+		node = node.next.Load()
+		node.mu.Lock()
+	}
+	if j < i || node == l.tail && !allowEnd {
+		node.mu.Unlock()
+		pred.mu.Unlock()
+		return nil, nil, l.indexError(i)
+	}
+	return pred, node, nil
+}
+
+func (l *LockCoupled[T]) indexError(i int) error {
+	return IndexOutOfRangeError(fmt.Sprintf("index %d out of range for list of size %d", i, l.Size()))
+}
+
+// snapshot copies the data in the list, from head to tail, into a slice
+func (l *LockCoupled[T]) snapshot() []T {
+	data := make([]T, 0, l.Size())
+	l.walk(func(_ int, item T) bool {
+		data = append(data, item)
+		return true
+	})
+	return data
+}
+
+// walk calls f with the index and data of every item from the head of the
+// list to the tail until f returns false.  It locks the next node before
+// releasing the current one, and holds the lock of the node whose data f was
+// called with until f returns.
+func (l *LockCoupled[T]) walk(f func(i int, data T) bool) {
+	node := l.head
+	node.mu.Lock()
+	defer func() { node.mu.Unlock() }()
+	for i := 0; ; i++ {
+		next := node.next.Load()
+		next.mu.Lock()
+		node.mu.Unlock()
+		node = next
+		if node == l.tail || !f(i, node.data) {
+			return
+		}
+	}
+}
+
+// link inserts node between pred and succ.  The caller must hold the locks
+// of pred and succ.
+func (l *LockCoupled[T]) link(node, pred, succ *coupledNode[T]) {
+	node.prev.Store(pred)
+	node.next.Store(succ)
+	node.owner.Store(l)
+	pred.next.Store(node)
+	succ.prev.Store(node)
+	l.size.Add(1)
+}
+
+// unlink takes node out from between pred and succ without clearing its
+// owner, so that an Element referencing it stays valid.  The caller must hold
+// the locks of node, pred and succ.
+func (l *LockCoupled[T]) unlink(node, pred, succ *coupledNode[T]) {
+	pred.next.Store(succ)
+	succ.prev.Store(pred)
+	node.prev.Store(nil)
+	node.next.Store(nil)
+	l.size.Add(-1)
+}
+
+// remove takes node out from between pred and succ and returns its data.  The
+// caller must hold the locks of node, pred and succ.
+func (l *LockCoupled[T]) remove(node, pred, succ *coupledNode[T]) T {
+	l.unlink(node, pred, succ)
+	node.owner.Store(nil)
+	return node.data
+}
@@ -0,0 +1,349 @@
+package lists
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockCoupledDeleteRunsAlongsidePushes(t *testing.T) {
+	list := NewLockCoupled[int]()
+	for i := 0; i < 10; i++ {
+		list.PushTail(i)
+	}
+	inPredicate := make(chan struct{})
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		list.Delete(0, func(data int) bool {
+			if data == 5 {
+				close(inPredicate)
+				<-release
+			}
+			return data%2 == 0
+		})
+	}()
+	<-inPredicate
+
+	// the Delete is parked in the middle of the list, so both ends and a
+	// scan of the front of the list must still make progress
+	done := make(chan struct{})
+	go func() {
+		list.PushHead(-1)
+		list.PushTail(10)
+		list.PopTail()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("pushes and pops at the ends should not wait for Delete")
+	}
+	close(release)
+	wg.Wait()
+
+	var items []int
+	for {
+		item, err := list.PopHead()
+		if err != nil {
+			break
+		}
+		items = append(items, item)
+	}
+	assert.Equal(t, []int{-1, 1, 3, 5, 7, 9}, items)
+}
+
+func TestLockCoupledPeek(t *testing.T) {
+	list := NewLockCoupled[int]()
+	_, err := list.PeekHead()
+	assert.IsType(t, EmptyListError(""), err)
+	_, err = list.PeekTail()
+	assert.IsType(t, EmptyListError(""), err)
+	list.PushTail(1)
+	list.PushTail(2)
+	head, err := list.PeekHead()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, head)
+	tail, err := list.PeekTail()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, tail)
+	assert.Equal(t, 2, list.Size(), "peeking should not remove items")
+}
+
+func TestLockCoupledIterators(t *testing.T) {
+	list := NewLockCoupled[int]()
+	for i := 0; i < 5; i++ {
+		list.PushTail(i * 10)
+	}
+	var indexes, items []int
+	for i, item := range list.All() {
+		indexes = append(indexes, i)
+		items = append(items, item)
+	}
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, indexes)
+	assert.Equal(t, []int{0, 10, 20, 30, 40}, items)
+
+	items = nil
+	for item := range list.Values() {
+		if item == 30 {
+			break
+		}
+		items = append(items, item)
+	}
+	assert.Equal(t, []int{0, 10, 20}, items)
+
+	// breaking out of the loop must release every lock
+	list.PushHead(-10)
+	list.PushTail(50)
+	assert.Equal(t, 7, list.Size())
+}
+
+func TestLockCoupledIteratorRunsAlongsidePushes(t *testing.T) {
+	list := NewLockCoupled[int]()
+	for i := 0; i < 5; i++ {
+		list.PushTail(i)
+	}
+	inLoop := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for item := range list.Values() {
+			if item == 2 {
+				close(inLoop)
+				<-release
+			}
+		}
+	}()
+	<-inLoop
+	pushed := make(chan struct{})
+	go func() {
+		list.PushHead(-1)
+		list.PushTail(5)
+		close(pushed)
+	}()
+	select {
+	case <-pushed:
+	case <-time.After(time.Second):
+		t.Fatal("pushes at the ends should not wait for an iterator in the middle")
+	}
+	close(release)
+	<-done
+}
+
+func TestLockCoupledIndexes(t *testing.T) {
+	list := NewLockCoupled[int]()
+	assert.NoError(t, list.InsertAt(0, 1))
+	assert.NoError(t, list.InsertAt(1, 3))
+	assert.NoError(t, list.InsertAt(1, 2))
+	assert.IsType(t, IndexOutOfRangeError(""), list.InsertAt(4, 4))
+	assert.IsType(t, IndexOutOfRangeError(""), list.InsertAt(-1, 4))
+
+	item, err := list.Get(1)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, item)
+	_, err = list.Get(3)
+	assert.IsType(t, IndexOutOfRangeError(""), err)
+	assert.NoError(t, list.Set(2, 30))
+	assert.IsType(t, IndexOutOfRangeError(""), list.Set(3, 4))
+
+	item, err = list.RemoveAt(0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, item)
+	_, err = list.RemoveAt(2)
+	assert.IsType(t, IndexOutOfRangeError(""), err)
+	assert.Equal(t, []int{2, 30}, slices.Collect(list.Values()))
+}
+
+func TestLockCoupledEnumerateAndBackward(t *testing.T) {
+	list := NewLockCoupled[int]()
+	for i := 0; i < 3; i++ {
+		list.PushTail(i)
+	}
+	var items []int
+	for i, item := range list.Enumerate() {
+		assert.Equal(t, i, item)
+		items = append(items, item)
+		// the loop body may modify the list
+		list.PushTail(item + 10)
+	}
+	assert.Equal(t, []int{0, 1, 2}, items)
+
+	items = nil
+	var indexes []int
+	for i, item := range list.Backward() {
+		indexes = append(indexes, i)
+		items = append(items, item)
+	}
+	assert.Equal(t, []int{5, 4, 3, 2, 1, 0}, indexes)
+	assert.Equal(t, []int{12, 11, 10, 2, 1, 0}, items)
+}
+
+func TestLockCoupledElements(t *testing.T) {
+	list := NewLockCoupled[int]()
+	two, err := list.PushTailElement(2)
+	assert.NoError(t, err)
+	one, err := list.PushHeadElement(1)
+	assert.NoError(t, err)
+	_, err = list.InsertAfter(two, 4)
+	assert.NoError(t, err)
+	three, err := list.InsertBefore(two, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 3, 2, 4}, slices.Collect(list.Values()))
+
+	assert.NoError(t, list.MoveToTail(one))
+	assert.NoError(t, list.MoveToHead(two))
+	assert.Equal(t, []int{2, 3, 4, 1}, slices.Collect(list.Values()))
+	value, err := list.Value(one)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	removed, err := list.Remove(three)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, removed)
+	_, err = list.Value(three)
+	assert.Equal(t, InvalidElementError("element has been removed from its list"), err)
+	_, err = list.Remove(nil)
+	assert.Equal(t, InvalidElementError("element is nil"), err)
+	assert.Equal(t, []int{2, 4, 1}, slices.Collect(list.Values()))
+
+	other := NewLockCoupled[int]()
+	_, err = other.Remove(one)
+	assert.Equal(t, InvalidElementError("element belongs to a different list"), err)
+	d := NewDoublyOf[int]()
+	e, _ := d.PushTailElement(1)
+	_, err = list.Value(e)
+	assert.Equal(t, InvalidElementError("element belongs to a different list"), err)
+	_, err = d.Value(one)
+	assert.Equal(t, InvalidElementError("element belongs to a different list"), err)
+}
+
+func TestLockCoupledElementsRunAlongsidePops(t *testing.T) {
+	list := NewLockCoupled[int]()
+	var elements []*Element[int]
+	for i := 0; i < 100; i++ {
+		e, _ := list.PushTailElement(i)
+		elements = append(elements, e)
+	}
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for i, e := range elements {
+			if i%2 == 0 {
+				list.MoveToHead(e)
+			} else {
+				list.MoveToTail(e)
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for range list.Values() {
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			list.PopTail()
+		}
+	}()
+	wg.Wait()
+	assert.Equal(t, 50, list.Size())
+	assert.Len(t, slices.Collect(list.Values()), 50)
+}
+
+func TestLockCoupledBounded(t *testing.T) {
+	reject := NewBoundedLockCoupled[int](2, Reject)
+	assert.NoError(t, reject.PushTail(1))
+	assert.NoError(t, reject.PushTail(2))
+	assert.IsType(t, CapacityExceededError(""), reject.PushTail(3))
+	assert.IsType(t, CapacityExceededError(""), reject.InsertAt(0, 3))
+	assert.Equal(t, OverflowStats{Rejected: 2}, reject.OverflowStats())
+	assert.Equal(t, 2, reject.Capacity())
+
+	evict := NewBoundedLockCoupled[int](2, Evict)
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, evict.PushTail(i))
+	}
+	assert.NoError(t, evict.PushHead(4))
+	assert.Equal(t, []int{4, 2}, slices.Collect(evict.Values()))
+
+	block := NewBoundedLockCoupled[int](1, Block)
+	assert.NoError(t, block.PushTail(1))
+	assert.IsType(t, CapacityExceededError(""), block.TryPushTail(2))
+	pushed := make(chan error)
+	go func() {
+		pushed <- block.PushTail(2)
+	}()
+	select {
+	case <-pushed:
+		t.Fatal("PushTail should wait for room")
+	case <-time.After(10 * time.Millisecond):
+	}
+	item, err := block.PopHead()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, item)
+	assert.NoError(t, <-pushed)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, block.PushHeadWait(ctx, 3))
+}
+
+func TestLockCoupledPopWait(t *testing.T) {
+	list := NewLockCoupled[int]()
+	popped := make(chan int)
+	go func() {
+		item, _ := list.PopHeadWait(context.Background())
+		popped <- item
+	}()
+	select {
+	case <-popped:
+		t.Fatal("PopHeadWait should wait for an item")
+	case <-time.After(10 * time.Millisecond):
+	}
+	list.PushTail(1)
+	assert.Equal(t, 1, <-popped)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := list.PopTailWait(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestLockCoupledClose(t *testing.T) {
+	list := NewLockCoupled[int]()
+	list.PushTail(1)
+	waiting := make(chan error)
+	full := NewBoundedLockCoupled[int](1, Block)
+	full.PushTail(1)
+	go func() {
+		waiting <- full.PushTailWait(context.Background(), 2)
+	}()
+	assert.NoError(t, list.Close())
+	assert.NoError(t, full.Close())
+	assert.IsType(t, ClosedListError(""), <-waiting)
+	assert.IsType(t, ClosedListError(""), list.Close())
+	assert.True(t, list.IsClosed())
+	assert.IsType(t, ClosedListError(""), list.PushHead(2))
+
+	select {
+	case <-list.Done():
+		t.Fatal("Done should stay open until the list is empty")
+	default:
+	}
+	item, err := list.PopTailWait(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, item)
+	<-list.Done()
+	_, err = list.PopHead()
+	assert.IsType(t, DrainedListError(""), err)
+	_, err = list.PopHeadWait(context.Background())
+	assert.IsType(t, DrainedListError(""), err)
+}
@@ -1,35 +1,112 @@
 package lists
 
-import "sync"
+import (
+	"context"
+	"fmt"
+	"iter"
+	"sync/atomic"
+)
 
-// Doubly goroutine-safe implementation of a doubly-linked list
-type Doubly struct {
-	head   *doublyNode
-	tail   *doublyNode
+// Doubly goroutine-safe implementation of a doubly-linked list holding items
+// of type T
+type Doubly[T any] struct {
+	head   *doublyNode[T]
+	tail   *doublyNode[T]
 	size   int
-	rwLock *sync.RWMutex
+	rwLock rwLocker
+	// notEmpty is signaled once for every item added to the list
+	notEmpty *waitCond
+	bounds   bounds
+	// owner is shared by every node linked into the list
+	owner *owner[T]
+	// opts are the options the list was created with, reused for the lists
+	// returned by SplitAt and SplitWhere
+	opts []Option
+	// tx is the transaction running in Do, if any, which records how to undo
+	// every change made to the list
+	tx *doublyTx[T]
 }
 
-type doublyNode struct {
-	Next *doublyNode
-	Prev *doublyNode
-	Data interface{}
+type doublyNode[T any] struct {
+	Next *doublyNode[T]
+	Prev *doublyNode[T]
+	Data T
+	// owner leads to the Doubly the node is linked into, or is nil once the
+	// node has been removed.  It is atomic so that an Element handed to the
+	// wrong list can be checked without holding the lock of the list that
+	// owns it.
+	owner atomic.Pointer[owner[T]]
 }
 
-// NewDoubly creates a new empty doubly-linked list
-func NewDoubly() *Doubly {
-	return &Doubly{
-		head:   nil,
-		tail:   nil,
-		size:   0,
-		rwLock: &sync.RWMutex{},
+// owner identifies the Doubly a set of nodes is linked into.  Concat and
+// SpliceAt move every node of one list into another in O(1) by forwarding the
+// old list's owner to the new list's owner instead of updating each node.
+type owner[T any] struct {
+	list *Doubly[T]
+	// next is the owner this one has been forwarded to, if any
+	next atomic.Pointer[owner[T]]
+}
+
+// list returns the Doubly node is linked into, or nil if it has been removed
+func (node *doublyNode[T]) list() *Doubly[T] {
+	first := node.owner.Load()
+	if first == nil {
+		return nil
+	}
+	o := first
+	for next := o.next.Load(); next != nil; next = o.next.Load() {
+		o = next
 	}
+	if o != first {
+		// shorten the path for next time
+		node.owner.CompareAndSwap(first, o)
+	}
+	return o.list
+}
+
+// NewDoubly creates a new empty doubly-linked list that can hold any type of
+// data.  Use NewDoublyOf to create a list of a specific type.
+func NewDoubly(opts ...Option) *UntypedDoubly {
+	return &UntypedDoubly{NewDoublyOf[interface{}](opts...)}
+}
+
+// NewDoublyOf creates a new empty doubly-linked list holding items of type T.
+// By default the list is guarded by a sync.RWMutex; see WithLocker and
+// WithoutLocking for alternatives.
+func NewDoublyOf[T any](opts ...Option) *Doubly[T] {
+	return newDoubly[T](bounds{}, opts)
+}
+
+// NewBoundedDoubly creates a new empty doubly-linked list holding at most
+// capacity items of type T.  policy decides what happens to items pushed while
+// the list is full.  opts work as they do for NewDoublyOf.  Panics if capacity
+// is less than 1.
+func NewBoundedDoubly[T any](capacity int, policy OverflowPolicy, opts ...Option) *Doubly[T] {
+	return newDoubly[T](newBounds(capacity, policy), opts)
+}
+
+func newDoubly[T any](b bounds, opts []Option) *Doubly[T] {
+	rwLock := newOptions(opts).rwLock
+	b.notFull = newWaitCond(rwLock)
+	b.done = make(chan struct{})
+	b.closeCh = make(chan struct{})
+	d := &Doubly[T]{
+		head:     nil,
+		tail:     nil,
+		size:     0,
+		rwLock:   rwLock,
+		notEmpty: newWaitCond(rwLock),
+		bounds:   b,
+		opts:     opts,
+	}
+	d.owner = &owner[T]{list: d}
+	return d
 }
 
 // Size of the list
 //
 // Runtime: O(1)
-func (d *Doubly) Size() int {
+func (d *Doubly[T]) Size() int {
 	d.rwLock.RLock()
 	defer d.rwLock.RUnlock()
 	return d.size
@@ -38,99 +115,295 @@ func (d *Doubly) Size() int {
 // IsEmpty true if the list contains no items
 //
 // Runtime: O(1)
-func (d *Doubly) IsEmpty() bool {
+func (d *Doubly[T]) IsEmpty() bool {
 	d.rwLock.RLock()
 	defer d.rwLock.RUnlock()
 	return d.head == nil
 }
 
-// PushHead adds data to the front of the list
+// PushHead adds data to the front of the list.  On a bounded list that is
+// full, the list's OverflowPolicy applies: Reject returns a
+// CapacityExceededError, Block waits for room, Evict removes the tail and
+// Drop discards the item without an error.  Returns a ClosedListError if the
+// list has been closed.
+//
+// Runtime: O(1)
+func (d *Doubly[T]) PushHead(data T) error {
+	d.rwLock.Lock()
+	defer d.rwLock.Unlock()
+	_, err := d.push(context.Background(), true, data, true)
+	return err
+}
+
+// PushTail adds data to the back of the list.  On a bounded list that is
+// full, the list's OverflowPolicy applies: Reject returns a
+// CapacityExceededError, Block waits for room, Evict removes the head and
+// Drop discards the item without an error.  Returns a ClosedListError if the
+// list has been closed.
+//
+// Runtime: O(1)
+func (d *Doubly[T]) PushTail(data T) error {
+	d.rwLock.Lock()
+	defer d.rwLock.Unlock()
+	_, err := d.push(context.Background(), true, data, false)
+	return err
+}
+
+// TryPushHead adds data to the front of the list without waiting.  Returns a
+// CapacityExceededError if the list is full and its policy is Reject or
+// Block, or a ClosedListError if the list has been closed.
+//
+// Runtime: O(1)
+func (d *Doubly[T]) TryPushHead(data T) error {
+	d.rwLock.Lock()
+	defer d.rwLock.Unlock()
+	_, err := d.push(context.Background(), false, data, true)
+	return err
+}
+
+// TryPushTail adds data to the back of the list without waiting.  Returns a
+// CapacityExceededError if the list is full and its policy is Reject or
+// Block, or a ClosedListError if the list has been closed.
+//
+// Runtime: O(1)
+func (d *Doubly[T]) TryPushTail(data T) error {
+	d.rwLock.Lock()
+	defer d.rwLock.Unlock()
+	_, err := d.push(context.Background(), false, data, false)
+	return err
+}
+
+// PushHeadWait adds data to the front of the list.  If the list is full and
+// its policy is Block, it waits for room and returns ctx.Err() if ctx is
+// done first.  Returns a CapacityExceededError if the list is full and its
+// policy is Reject, or a ClosedListError if the list has been closed, even
+// while waiting.
+//
+// Runtime: O(1)
+func (d *Doubly[T]) PushHeadWait(ctx context.Context, data T) error {
+	d.rwLock.Lock()
+	defer d.rwLock.Unlock()
+	_, err := d.push(ctx, true, data, true)
+	return err
+}
+
+// PushTailWait adds data to the back of the list.  If the list is full and
+// its policy is Block, it waits for room and returns ctx.Err() if ctx is
+// done first.  Returns a CapacityExceededError if the list is full and its
+// policy is Reject, or a ClosedListError if the list has been closed, even
+// while waiting.
+//
+// Runtime: O(1)
+func (d *Doubly[T]) PushTailWait(ctx context.Context, data T) error {
+	d.rwLock.Lock()
+	defer d.rwLock.Unlock()
+	_, err := d.push(ctx, true, data, false)
+	return err
+}
+
+// Capacity returns the maximum number of items the list can hold, or 0 if
+// the list is unbounded
+//
+// Runtime: O(1)
+func (d *Doubly[T]) Capacity() int {
+	return d.bounds.capacity
+}
+
+// OverflowStats returns how many items the list's OverflowPolicy has
+// rejected, evicted or dropped so far
+//
+// Runtime: O(1)
+func (d *Doubly[T]) OverflowStats() OverflowStats {
+	d.rwLock.RLock()
+	defer d.rwLock.RUnlock()
+	return d.bounds.stats
+}
+
+// PopHead removes data from the front of the list.  Returns an
+// EmptyListError if there are no items in the list, or a DrainedListError if
+// the list has also been closed.
 //
 // Runtime: O(1)
-func (d *Doubly) PushHead(data interface{}) {
+func (d *Doubly[T]) PopHead() (data T, err error) {
 	d.rwLock.Lock()
 	defer d.rwLock.Unlock()
 	if d.head == nil {
-		d.head = &doublyNode{Next: nil, Prev: nil, Data: data}
-		d.tail = d.head
-	} else {
-		d.head.Prev = &doublyNode{Next: d.head, Prev: nil, Data: data}
-		d.head = d.head.Prev
+		return data, d.emptyError()
 	}
-	d.size++
+	return d.popHead(), nil
 }
 
-// PushTail adds data to the back of the list
+// PopTail removes data from the back of the list.  Returns an
+// EmptyListError if there are no items in the list, or a DrainedListError if
+// the list has also been closed.
 //
 // Runtime: O(1)
-func (d *Doubly) PushTail(data interface{}) {
+func (d *Doubly[T]) PopTail() (data T, err error) {
 	d.rwLock.Lock()
 	defer d.rwLock.Unlock()
 	if d.head == nil {
-		d.head = &doublyNode{Next: nil, Prev: nil, Data: data}
-		d.tail = d.head
-	} else {
-		d.tail.Next = &doublyNode{Next: nil, Prev: d.tail, Data: data}
-		d.tail = d.tail.Next
+		return data, d.emptyError()
 	}
-	d.size++
+	return d.popTail(), nil
 }
 
-// PopHead removes data from the front of the list.  Returns an
-// EmptyListError if there are no items in the list.
+// PopHeadWait removes data from the front of the list, waiting for an item
+// to be added if the list is empty.  Goroutines waiting on the same list are
+// handed items in the order they started waiting, whichever end they pop
+// from.  PopHead, PopTail and other methods that don't wait can still take
+// an item meant for a waiting goroutine, which then keeps its place at the
+// front of the line.  Returns ctx.Err() if ctx is cancelled or its deadline
+// passes before an item is available, or a DrainedListError once the list
+// has been closed and emptied.
 //
 // Runtime: O(1)
-func (d *Doubly) PopHead() (data interface{}, err error) {
+func (d *Doubly[T]) PopHeadWait(ctx context.Context) (data T, err error) {
 	d.rwLock.Lock()
 	defer d.rwLock.Unlock()
-	if d.head == nil {
-		return "", EmptyListError("can't remove an item from an empty list")
+	if err = d.waitNotEmpty(ctx); err != nil {
+		return
 	}
-	data = d.head.Data
-	d.head = d.head.Next
+	return d.popHead(), nil
+}
+
+// PopTailWait removes data from the back of the list, waiting for an item to
+// be added if the list is empty.  Waiting goroutines are handed items in
+// the order they started waiting, as for PopHeadWait.  Returns ctx.Err() if
+// ctx is cancelled or its deadline passes before an item is available, or a
+// DrainedListError once the list has been closed and emptied.
+//
+// Runtime: O(1)
+func (d *Doubly[T]) PopTailWait(ctx context.Context) (data T, err error) {
+	d.rwLock.Lock()
+	defer d.rwLock.Unlock()
+	if err = d.waitNotEmpty(ctx); err != nil {
+		return
+	}
+	return d.popTail(), nil
+}
+
+// PeekHead returns the data at the front of the list without removing it.
+// Returns an EmptyListError if there are no items in the list.
+//
+// Runtime: O(1)
+func (d *Doubly[T]) PeekHead() (data T, err error) {
+	d.rwLock.RLock()
+	defer d.rwLock.RUnlock()
 	if d.head == nil {
-		d.tail = nil
-	} else {
-		d.head.Prev = nil
+		return data, EmptyListError("can't peek at an empty list")
 	}
-	d.size--
-	return
+	return d.head.Data, nil
 }
 
-// PopTail removes data from the back of the list.  Returns an
-// EmptyListError if there are no items in the list.
+// PeekTail returns the data at the back of the list without removing it.
+// Returns an EmptyListError if there are no items in the list.
 //
 // Runtime: O(1)
-func (d *Doubly) PopTail() (data interface{}, err error) {
+func (d *Doubly[T]) PeekTail() (data T, err error) {
+	d.rwLock.RLock()
+	defer d.rwLock.RUnlock()
+	if d.tail == nil {
+		return data, EmptyListError("can't peek at an empty list")
+	}
+	return d.tail.Data, nil
+}
+
+// PopHeadIf removes data from the front of the list only if pred returns true
+// for it.  The check and the removal happen under a single lock, so no other
+// goroutine can change the head in between.  Returns a NoMatchError if pred
+// returns false, an EmptyListError if there are no items in the list, or a
+// DrainedListError if the list has also been closed.
+//
+// Runtime: O(1)
+func (d *Doubly[T]) PopHeadIf(pred func(data T) bool) (data T, err error) {
 	d.rwLock.Lock()
 	defer d.rwLock.Unlock()
 	if d.head == nil {
-		return "", EmptyListError("can't remove an item from an empty list")
+		return data, d.emptyError()
 	}
-	data = d.tail.Data
-	if d.head == d.tail {
-		d.head, d.tail = nil, nil
-	} else {
-		d.tail = d.tail.Prev
-		d.tail.Next = nil
+	if !pred(d.head.Data) {
+		return data, NoMatchError("item at the head doesn't match")
 	}
-	d.size--
-	return
+	return d.popHead(), nil
+}
+
+// PopTailIf removes data from the back of the list only if pred returns true
+// for it.  The check and the removal happen under a single lock, so no other
+// goroutine can change the tail in between.  Returns a NoMatchError if pred
+// returns false, an EmptyListError if there are no items in the list, or a
+// DrainedListError if the list has also been closed.
+//
+// Runtime: O(1)
+func (d *Doubly[T]) PopTailIf(pred func(data T) bool) (data T, err error) {
+	d.rwLock.Lock()
+	defer d.rwLock.Unlock()
+	if d.tail == nil {
+		return data, d.emptyError()
+	}
+	if !pred(d.tail.Data) {
+		return data, NoMatchError("item at the tail doesn't match")
+	}
+	return d.popTail(), nil
+}
+
+// SwapHead replaces the data at the front of the list with new, but only if
+// eq reports that it is equal to old, like a compare-and-swap.  Returns
+// whether the data was replaced, which is false if the list is empty.
+//
+// Runtime: O(1)
+func (d *Doubly[T]) SwapHead(old, new T, eq func(a, b T) bool) (swapped bool) {
+	d.rwLock.Lock()
+	defer d.rwLock.Unlock()
+	if d.head == nil || !eq(d.head.Data, old) {
+		return false
+	}
+	d.head.Data = new
+	return true
+}
+
+// Close stops any more items from being added to the list: every push
+// returns a ClosedListError from then on.  Items already in the list can
+// still be removed, and once they have all gone every pop returns a
+// DrainedListError.  Goroutines waiting in PopHeadWait or PopTailWait on an
+// empty list, or waiting for room in PushHeadWait or PushTailWait, are woken.
+// Returns a ClosedListError if the list was already closed.
+//
+// Runtime: O(1)
+func (d *Doubly[T]) Close() error {
+	d.rwLock.Lock()
+	defer d.rwLock.Unlock()
+	if err := d.bounds.close(d.size); err != nil {
+		return err
+	}
+	d.notEmpty.Broadcast()
+	return nil
+}
+
+// IsClosed returns true if Close has been called
+//
+// Runtime: O(1)
+func (d *Doubly[T]) IsClosed() bool {
+	d.rwLock.RLock()
+	defer d.rwLock.RUnlock()
+	return d.bounds.closed
+}
+
+// Done returns a channel that is closed once the list has been closed and
+// every item has been removed from it
+//
+// Runtime: O(1)
+func (d *Doubly[T]) Done() <-chan struct{} {
+	return d.bounds.done
 }
 
 // Contains returns true if list contains any data where the comparison
 // function returns true.  Moves from the head of the list to the tail.
 //
 // Runtime: O(n)
-func (d *Doubly) Contains(comparison func(data interface{}) (exists bool)) bool {
+func (d *Doubly[T]) Contains(comparison func(data T) (exists bool)) bool {
 	d.rwLock.RLock()
 	defer d.rwLock.RUnlock()
-	for tmp := d.head; tmp != nil; tmp = tmp.Next {
-		if comparison(tmp.Data) {
-			return true
-		}
-	}
-	return false
+	return d.contains(comparison)
 }
 
 // Delete numItems data in the list based on the provided comparison function.
@@ -140,32 +413,372 @@ func (d *Doubly) Contains(comparison func(data interface{}) (exists bool)) bool
 // then all data in the list is scanned.
 //
 // Runtime: O(n)
-func (d *Doubly) Delete(numItems int, comparison func(data interface{}) (shouldDelete bool)) (numDeleted int) {
+func (d *Doubly[T]) Delete(numItems int, comparison func(data T) (shouldDelete bool)) (numDeleted int) {
 	d.rwLock.Lock()
 	defer d.rwLock.Unlock()
-	if d.head == nil {
-		return
+	return d.deleteMatches(numItems, comparison)
+}
+
+// Get returns the data at index i, counting from 0 at the head of the list.
+// Returns an IndexOutOfRangeError if i is not in [0, Size()).
+//
+// Runtime: O(n), walking from whichever end of the list is closer to i
+func (d *Doubly[T]) Get(i int) (data T, err error) {
+	d.rwLock.RLock()
+	defer d.rwLock.RUnlock()
+	return d.get(i)
+}
+
+// Set replaces the data at index i.  Returns an IndexOutOfRangeError if i is
+// not in [0, Size()).
+//
+// Runtime: O(n), walking from whichever end of the list is closer to i
+func (d *Doubly[T]) Set(i int, data T) error {
+	d.rwLock.Lock()
+	defer d.rwLock.Unlock()
+	return d.set(i, data)
+}
+
+// InsertAt adds data at index i, moving the item previously at i and every
+// item after it one position towards the tail.  i may equal Size() to add
+// data to the back of the list.  Returns an IndexOutOfRangeError if i is not
+// in [0, Size()].  On a bounded list that is full, the Drop policy discards
+// data and every other policy returns a CapacityExceededError.
+//
+// Runtime: O(n), walking from whichever end of the list is closer to i
+func (d *Doubly[T]) InsertAt(i int, data T) error {
+	d.rwLock.Lock()
+	defer d.rwLock.Unlock()
+	return d.insertAt(i, data)
+}
+
+// RemoveAt removes the data at index i.  Returns an IndexOutOfRangeError if i
+// is not in [0, Size()).
+//
+// Runtime: O(n), walking from whichever end of the list is closer to i
+func (d *Doubly[T]) RemoveAt(i int) (data T, err error) {
+	d.rwLock.Lock()
+	defer d.rwLock.Unlock()
+	return d.removeAt(i)
+}
+
+// All returns an iterator over the index and data of every item from the
+// head of the list to the tail.  The read lock is held for the whole loop, so
+// the loop body must not call other methods on the list; use Enumerate if it
+// needs to.
+//
+// Runtime: O(n)
+func (d *Doubly[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		d.rwLock.RLock()
+		defer d.rwLock.RUnlock()
+		i := 0
+		for tmp := d.head; tmp != nil; tmp = tmp.Next {
+			if !yield(i, tmp.Data) {
+				return
+			}
+			i++
+		}
 	}
-	for tmp := d.head; tmp != nil; tmp = tmp.Next {
-		if comparison(tmp.Data) {
-			pred := tmp.Prev
-			succ := tmp.Next
-			if succ == nil {
-				d.tail = pred
-			} else {
-				succ.Prev = pred
+}
+
+// Backward returns an iterator over the index and data of every item from the
+// tail of the list to the head, like All in reverse.  The read lock is held
+// for the whole loop, so the loop body must not call other methods on the
+// list.
+//
+// Runtime: O(n)
+func (d *Doubly[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		d.rwLock.RLock()
+		defer d.rwLock.RUnlock()
+		i := d.size - 1
+		for tmp := d.tail; tmp != nil; tmp = tmp.Prev {
+			if !yield(i, tmp.Data) {
+				return
 			}
-			if pred == nil {
-				d.head = succ
-			} else {
-				pred.Next = succ
+			i--
+		}
+	}
+}
+
+// Values returns an iterator over the data of every item from the head of the
+// list to the tail.  Like All, the read lock is held for the whole loop.
+//
+// Runtime: O(n)
+func (d *Doubly[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		d.rwLock.RLock()
+		defer d.rwLock.RUnlock()
+		for tmp := d.head; tmp != nil; tmp = tmp.Next {
+			if !yield(tmp.Data) {
+				return
+			}
+		}
+	}
+}
+
+// Enumerate returns an iterator over the index and data of every item from
+// the head of the list to the tail.  Each iteration works from a snapshot of
+// the list taken under the read lock when the loop starts, so the lock is not
+// held while the loop body runs and the body may modify the list.  Changes
+// made during the loop are not seen by it.
+//
+// Runtime: O(n), plus O(n) memory for the snapshot
+func (d *Doubly[T]) Enumerate() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, data := range d.snapshot() {
+			if !yield(i, data) {
+				return
+			}
+		}
+	}
+}
+
+// snapshot copies the data in the list, from head to tail, into a slice
+func (d *Doubly[T]) snapshot() []T {
+	d.rwLock.RLock()
+	defer d.rwLock.RUnlock()
+	data := make([]T, 0, d.size)
+	for tmp := d.head; tmp != nil; tmp = tmp.Next {
+		data = append(data, tmp.Data)
+	}
+	return data
+}
+
+// push adds data to the front or back of the list after applying the
+// overflow policy and returns its node, or nil if it wasn't added.  See
+// bounds.admit for how ctx and wait are used.  The caller must hold the
+// write lock.
+func (d *Doubly[T]) push(ctx context.Context, wait bool, data T, atHead bool) (node *doublyNode[T], err error) {
+	ok, err := d.bounds.admit(ctx, wait, &d.size, func() {
+		if atHead {
+			d.popTail()
+		} else {
+			d.popHead()
+		}
+	})
+	if ok {
+		if atHead {
+			node = d.pushHead(data)
+		} else {
+			node = d.pushTail(data)
+		}
+	}
+	return
+}
+
+// pushHead adds data to the front of the list and returns its node.  The
+// caller must hold the write lock.
+func (d *Doubly[T]) pushHead(data T) *doublyNode[T] {
+	node := &doublyNode[T]{Data: data}
+	d.link(node, nil)
+	return node
+}
+
+// pushTail adds data to the back of the list and returns its node.  The
+// caller must hold the write lock.
+func (d *Doubly[T]) pushTail(data T) *doublyNode[T] {
+	node := &doublyNode[T]{Data: data}
+	d.link(node, d.tail)
+	return node
+}
+
+// popHead removes data from the front of a non-empty list.  The caller must
+// hold the write lock.
+func (d *Doubly[T]) popHead() T {
+	return d.remove(d.head)
+}
+
+// popTail removes data from the back of a non-empty list.  The caller must
+// hold the write lock.
+func (d *Doubly[T]) popTail() T {
+	return d.remove(d.tail)
+}
+
+// link adds node to the list after pred, or at the front of the list if pred
+// is nil.  The caller must hold the write lock.
+func (d *Doubly[T]) link(node, pred *doublyNode[T]) {
+	d.splice(node, pred)
+	node.owner.Store(d.owner)
+	d.size++
+	d.journal(doublyUndo[T]{kind: undoInsert, node: node})
+	d.notEmpty.Signal()
+}
+
+// remove takes node out of the list and returns its data.  The caller must
+// hold the write lock.
+func (d *Doubly[T]) remove(node *doublyNode[T]) T {
+	d.journal(doublyUndo[T]{kind: undoRemove, node: node, pred: node.Prev})
+	d.unsplice(node)
+	node.owner.Store(nil)
+	d.size--
+	d.bounds.removed(d.size)
+	return node.Data
+}
+
+// splice connects node to the list after pred, or at the front of the list if
+// pred is nil, without any other bookkeeping.  The caller must hold the write
+// lock.
+func (d *Doubly[T]) splice(node, pred *doublyNode[T]) {
+	var succ *doublyNode[T]
+	if pred == nil {
+		succ = d.head
+		d.head = node
+	} else {
+		succ = pred.Next
+		pred.Next = node
+	}
+	if succ == nil {
+		d.tail = node
+	} else {
+		succ.Prev = node
+	}
+	node.Prev, node.Next = pred, succ
+}
+
+// unsplice disconnects node from its neighbours without any other
+// bookkeeping.  The caller must hold the write lock.
+func (d *Doubly[T]) unsplice(node *doublyNode[T]) {
+	pred := node.Prev
+	succ := node.Next
+	if succ == nil {
+		d.tail = pred
+	} else {
+		succ.Prev = pred
+	}
+	if pred == nil {
+		d.head = succ
+	} else {
+		pred.Next = succ
+	}
+	node.Next, node.Prev = nil, nil
+}
+
+// nodeAt returns the node at index i, which must be in range, starting from
+// whichever end of the list is closer.  The caller must hold the read or
+// write lock.
+func (d *Doubly[T]) nodeAt(i int) (tmp *doublyNode[T]) {
+	if i < d.size/2 {
+		for tmp = d.head; i > 0; i-- {
+			tmp = tmp.Next
+		}
+	} else {
+		for tmp = d.tail; i < d.size-1; i++ {
+			tmp = tmp.Prev
+		}
+	}
+	return
+}
+
+// checkIndex returns an IndexOutOfRangeError if i is not in [0, limit).  The
+// caller must hold the read or write lock.
+func (d *Doubly[T]) checkIndex(i, limit int) error {
+	if i < 0 || i >= limit {
+		return IndexOutOfRangeError(fmt.Sprintf("index %d out of range for list of size %d", i, d.size))
+	}
+	return nil
+}
+
+// waitNotEmpty waits until the caller may take an item from the list, ctx is
+// done or the list is closed while empty.  Callers that are already waiting
+// go first.  Once the list is closed nothing more will be added, so the
+// remaining items go to whoever gets to them.  The caller must hold the
+// write lock.
+func (d *Doubly[T]) waitNotEmpty(ctx context.Context) error {
+	var ticket uint64
+	for {
+		if d.bounds.closed {
+			if d.head == nil {
+				return d.emptyError()
 			}
-			d.size--
+			return nil
+		}
+		if d.notEmpty.Ready(ticket, d.size) {
+			return nil
+		}
+		if err := d.notEmpty.WaitTurn(ctx, &ticket); err != nil {
+			return err
+		}
+	}
+}
+
+// emptyError returns the error for popping from the list while it is empty.
+// The caller must hold the read or write lock.
+func (d *Doubly[T]) emptyError() error {
+	if d.bounds.closed {
+		return DrainedListError("can't remove an item from a closed and empty list")
+	}
+	return EmptyListError("can't remove an item from an empty list")
+}
+
+// contains is Contains without locking.  The caller must hold the read or
+// write lock.
+func (d *Doubly[T]) contains(comparison func(data T) (exists bool)) bool {
+	for tmp := d.head; tmp != nil; tmp = tmp.Next {
+		if comparison(tmp.Data) {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteMatches is Delete without locking.  The caller must hold the write
+// lock.
+func (d *Doubly[T]) deleteMatches(numItems int, comparison func(data T) (shouldDelete bool)) (numDeleted int) {
+	for tmp := d.head; tmp != nil; {
+		next := tmp.Next
+		if comparison(tmp.Data) {
+			d.remove(tmp)
 			numDeleted++
 			if numItems == numDeleted {
 				return
 			}
 		}
+		tmp = next
 	}
 	return
 }
+
+// get is Get without locking.  The caller must hold the read or write lock.
+func (d *Doubly[T]) get(i int) (data T, err error) {
+	if err = d.checkIndex(i, d.size); err != nil {
+		return
+	}
+	return d.nodeAt(i).Data, nil
+}
+
+// set is Set without locking.  The caller must hold the write lock.
+func (d *Doubly[T]) set(i int, data T) error {
+	if err := d.checkIndex(i, d.size); err != nil {
+		return err
+	}
+	node := d.nodeAt(i)
+	d.journal(doublyUndo[T]{kind: undoSet, node: node, data: node.Data})
+	node.Data = data
+	return nil
+}
+
+// insertAt is InsertAt without locking.  The caller must hold the write lock.
+func (d *Doubly[T]) insertAt(i int, data T) error {
+	if err := d.checkIndex(i, d.size+1); err != nil {
+		return err
+	}
+	if ok, err := d.bounds.admit(context.Background(), false, &d.size, nil); !ok {
+		return err
+	}
+	if i == d.size {
+		d.pushTail(data)
+	} else {
+		d.link(&doublyNode[T]{Data: data}, d.nodeAt(i).Prev)
+	}
+	return nil
+}
+
+// removeAt is RemoveAt without locking.  The caller must hold the write lock.
+func (d *Doubly[T]) removeAt(i int) (data T, err error) {
+	if err = d.checkIndex(i, d.size); err != nil {
+		return
+	}
+	return d.remove(d.nodeAt(i)), nil
+}
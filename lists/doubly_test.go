@@ -189,3 +189,20 @@ func (suite *DoublyTestSuite) TestIsEmpty() {
 	list.Delete(0, suite.findExact(data))
 	assert.True(suite.T(), list.IsEmpty(), "Delete")
 }
+
+func (suite *DoublyTestSuite) TestBackward() {
+	list := NewDoublyOf[string]()
+	for range list.Backward() {
+		suite.T().Fatal("empty list should not yield anything")
+	}
+	for _, item := range suite.data {
+		list.PushTail(item)
+	}
+	expected := len(suite.data) - 1
+	for i, item := range list.Backward() {
+		assert.Equal(suite.T(), expected, i, "index is incorrect")
+		assert.Exactly(suite.T(), suite.data[i], item, "list item data[%d] is incorrect", i)
+		expected--
+	}
+	assert.Equal(suite.T(), -1, expected)
+}
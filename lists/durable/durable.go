@@ -0,0 +1,563 @@
+/*
+Package durable provides a deque and a queue that survive restarts by
+recording every change on disk.
+
+A Deque keeps its items in memory in a lists.Doubly and, before applying a
+change, appends a record of it to a write-ahead log in its directory.
+Opening the directory again rebuilds the items by reading the latest
+snapshot and replaying the log on top of it.  If the process or machine
+crashed while a record was being written, the cut-short or corrupted tail of
+the log is detected by its checksum and discarded, so the deque comes back
+as it was after the last complete change.
+
+To stop the log growing forever, once it holds enough records the Deque
+writes all of its items to a new snapshot and starts a new, empty log:
+
+	jobs, err := durable.Open[Job]("/var/lib/jobs", durable.WithSyncEvery(10))
+	if err != nil {
+		return err
+	}
+	defer jobs.Close()
+	jobs.PushTail(job)
+
+A Deque holds every item in memory.  For backlogs too big for that, a
+SegmentQueue is a FIFO queue that keeps its items in segment files and reads
+them back through mmap, holding only the segments at the front and back of
+the queue open.
+
+Items are stored as JSON, so T must survive a round trip through
+encoding/json.  A directory must only be opened by one Deque or SegmentQueue
+at a time.
+*/
+package durable
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"iter"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/suicidejack/go-various/lists"
+)
+
+// Deque goroutine-safe, disk-backed double-ended queue holding items of type
+// T.  It implements lists.Deque, so it can replace a Doubly used as a deque or
+// queue.  An error writing to the log is returned by the change that hit it
+// and recorded for Err to return.  After any error writing to disk the Deque
+// refuses every further change, since the log may no longer match the items
+// in memory.
+type Deque[T any] struct {
+	mu   sync.Mutex
+	list *lists.Doubly[T]
+	dir  string
+	opts options
+	log  *os.File
+	// gen is the generation of the snapshot, which is 0 before the first one
+	// is written
+	gen uint64
+	// records is the number of records in the log
+	records int
+	// unsynced is the number of records written since the log was last
+	// fsynced
+	unsynced int
+	recovery Recovery
+	// err is the first error writing to disk
+	err    error
+	closed bool
+}
+
+// Recovery describes what Open found on disk
+type Recovery struct {
+	// SnapshotItems is the number of items read from the snapshot
+	SnapshotItems int
+	// Records is the number of log records replayed on top of the snapshot
+	Records int
+	// DiscardedBytes is the size of the cut-short or corrupted tail removed
+	// from the end of the log
+	DiscardedBytes int64
+}
+
+var _ lists.Deque[interface{}] = (*Deque[interface{}])(nil)
+
+// Open opens the Deque stored in dir, creating dir and an empty Deque if
+// they don't exist.  A cut-short or corrupted tail of the log is discarded,
+// as described by Recovery.  Returns a CorruptSnapshotError if the snapshot
+// can't be read, or any error from the file system.
+func Open[T any](dir string, opts ...Option) (*Deque[T], error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	q := &Deque[T]{
+		list: lists.NewDoublyOf[T](lists.WithoutLocking()),
+		dir:  dir,
+		opts: newOptions(opts),
+	}
+	if err := q.loadSnapshot(); err != nil {
+		return nil, err
+	}
+	if err := q.replayLog(); err != nil {
+		return nil, err
+	}
+	q.removeStaleFiles()
+	return q, nil
+}
+
+// Size of the deque
+//
+// Runtime: O(1)
+func (q *Deque[T]) Size() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.list.Size()
+}
+
+// IsEmpty returns true if the deque contains no items
+//
+// Runtime: O(1)
+func (q *Deque[T]) IsEmpty() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.list.IsEmpty()
+}
+
+// PushHead is the same as TryPushHead.  A failure to write to the log is
+// also returned by Err.
+//
+// Runtime: O(1), plus writing the record and any compaction
+func (q *Deque[T]) PushHead(data T) error {
+	return q.TryPushHead(data)
+}
+
+// PushTail is the same as TryPushTail.  A failure to write to the log is
+// also returned by Err.
+//
+// Runtime: O(1), plus writing the record and any compaction
+func (q *Deque[T]) PushTail(data T) error {
+	return q.TryPushTail(data)
+}
+
+// TryPushHead adds data to the front of the deque and returns any error
+// encoding data, including JSON that can't be decoded back into a T when the
+// log is replayed, or writing it to the log, in which case data isn't added.
+// Returns a ClosedError if the deque has been closed.
+//
+// Runtime: O(1), plus writing the record and any compaction
+func (q *Deque[T]) TryPushHead(data T) error {
+	return q.push(opPushHead, data)
+}
+
+// TryPushTail adds data to the back of the deque and returns any error
+// encoding data, including JSON that can't be decoded back into a T when the
+// log is replayed, or writing it to the log, in which case data isn't added.
+// Returns a ClosedError if the deque has been closed.
+//
+// Runtime: O(1), plus writing the record and any compaction
+func (q *Deque[T]) TryPushTail(data T) error {
+	return q.push(opPushTail, data)
+}
+
+// PopHead removes data from the front of the deque.  Returns a
+// lists.EmptyListError if there are no items in the deque, a ClosedError if
+// it has been closed, or any error writing to the log, in which case nothing
+// is removed.
+//
+// Runtime: O(1), plus writing the record and any compaction
+func (q *Deque[T]) PopHead() (data T, err error) {
+	return q.pop(opPopHead)
+}
+
+// PopTail removes data from the back of the deque and fails like PopHead.
+//
+// Runtime: O(1), plus writing the record and any compaction
+func (q *Deque[T]) PopTail() (data T, err error) {
+	return q.pop(opPopTail)
+}
+
+// Contains returns true if comparison returns true for any item in the deque
+//
+// Runtime: O(n)
+func (q *Deque[T]) Contains(comparison func(data T) (exists bool)) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.list.Contains(comparison)
+}
+
+// Delete removes up to numItems items that comparison returns true for,
+// starting from the head, and records which ones in the log.  Deletes every
+// match if numItems is less than 1.  Returns the number of items removed,
+// which is 0 if the change can't be written to the log; the error is then
+// returned by Err.
+//
+// Runtime: O(n) to find the items, plus O(n) for each item removed
+func (q *Deque[T]) Delete(numItems int, comparison func(data T) (shouldDelete bool)) (numDeleted int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var indexes []int
+	for i, data := range q.list.All() {
+		if comparison(data) {
+			indexes = append(indexes, i)
+			if len(indexes) == numItems {
+				break
+			}
+		}
+	}
+	if len(indexes) == 0 {
+		return 0
+	}
+	encoded, err := json.Marshal(indexes)
+	if err != nil {
+		return 0
+	}
+	if _, err = q.change(append([]byte{opDelete}, encoded...)); err != nil {
+		return 0
+	}
+	return len(indexes)
+}
+
+// Values returns an iterator over the data of every item from the head of
+// the deque to the tail.  It ranges over a copy of the items taken when the
+// loop starts, so the loop body may modify the deque.
+//
+// Runtime: O(n), plus O(n) memory for the copy
+func (q *Deque[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		q.mu.Lock()
+		items := make([]T, 0, q.list.Size())
+		for data := range q.list.Values() {
+			items = append(items, data)
+		}
+		q.mu.Unlock()
+		for _, data := range items {
+			if !yield(data) {
+				return
+			}
+		}
+	}
+}
+
+// Err returns the first error writing to disk, after which the deque
+// refuses any more changes, or nil.
+func (q *Deque[T]) Err() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.err
+}
+
+// Recovery describes what Open found on disk when it opened the deque
+func (q *Deque[T]) Recovery() Recovery {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.recovery
+}
+
+// Sync fsyncs the log, making every change so far durable even with a
+// WithSyncEvery setting that would otherwise delay it.
+func (q *Deque[T]) Sync() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err := q.checkWritable(); err != nil {
+		return err
+	}
+	return q.sync()
+}
+
+// Compact writes every item to a new snapshot and starts a new, empty log.
+// It happens automatically as set by WithCompactEvery.
+//
+// Runtime: O(n)
+func (q *Deque[T]) Compact() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err := q.checkWritable(); err != nil {
+		return err
+	}
+	return q.compact()
+}
+
+// Close fsyncs and closes the log.  The items stay readable but no more
+// changes can be made.  Returns a ClosedError if the deque was already closed.
+func (q *Deque[T]) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ClosedError("deque is already closed")
+	}
+	q.closed = true
+	err := q.log.Sync()
+	if closeErr := q.log.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (q *Deque[T]) push(op byte, data T) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	// replaying the log decodes the record, so make sure that will work
+	// before writing it
+	var decoded T
+	if err = json.Unmarshal(encoded, &decoded); err != nil {
+		return err
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err = q.write(append([]byte{op}, encoded...)); err != nil {
+		return err
+	}
+	// push data itself rather than its decoded copy, which may differ, such
+	// as a float64 for an int in a Deque[any]
+	if op == opPushHead {
+		q.list.PushHead(data)
+	} else {
+		q.list.PushTail(data)
+	}
+	q.compactIfDue()
+	return nil
+}
+
+func (q *Deque[T]) pop(op byte) (data T, err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err = q.checkWritable(); err != nil {
+		return
+	}
+	if q.list.IsEmpty() {
+		_, err = q.list.PopHead()
+		return data, err
+	}
+	return q.change([]byte{op})
+}
+
+// change writes the record with payload to the log, applies it to the items
+// in memory and compacts the log if it is due.  Returns the item removed by
+// a pop.  The caller must hold mu.
+func (q *Deque[T]) change(payload []byte) (data T, err error) {
+	if err = q.write(payload); err != nil {
+		return
+	}
+	if data, err = q.apply(payload); err != nil {
+		// the record was built from the items in memory, so this can't
+		// happen unless the deque itself is broken
+		panic(err)
+	}
+	q.compactIfDue()
+	return data, nil
+}
+
+// write appends the record with payload to the log, and fsyncs it if that is
+// due.  The caller must hold mu.
+func (q *Deque[T]) write(payload []byte) error {
+	if err := q.checkWritable(); err != nil {
+		return err
+	}
+	if _, err := q.log.Write(appendRecord(nil, payload)); err != nil {
+		q.err = err
+		return err
+	}
+	q.records++
+	q.unsynced++
+	if q.opts.syncEvery > 0 && q.unsynced >= q.opts.syncEvery {
+		return q.sync()
+	}
+	return nil
+}
+
+// compactIfDue compacts the log if WithCompactEvery says it is due.  A
+// failed compaction is recorded in q.err, but the change that made it due
+// has already been made.  The caller must hold mu.
+func (q *Deque[T]) compactIfDue() {
+	if q.opts.compactEvery > 0 && q.records >= q.opts.compactEvery {
+		q.compact()
+	}
+}
+
+// apply makes the change described by a log record's payload to the items
+// in memory.  Returns an error, and makes no change, if the payload doesn't
+// describe a change that is possible.  The caller must hold mu.
+func (q *Deque[T]) apply(payload []byte) (data T, err error) {
+	if len(payload) == 0 {
+		return data, badRecordError("empty log record")
+	}
+	switch op, body := payload[0], payload[1:]; op {
+	case opPushHead, opPushTail:
+		if err = json.Unmarshal(body, &data); err != nil {
+			return
+		}
+		if op == opPushHead {
+			q.list.PushHead(data)
+		} else {
+			q.list.PushTail(data)
+		}
+		return data, nil
+	case opPopHead:
+		return q.list.PopHead()
+	case opPopTail:
+		return q.list.PopTail()
+	case opDelete:
+		var indexes []int
+		if err = json.Unmarshal(body, &indexes); err != nil {
+			return
+		}
+		for i, index := range indexes {
+			if index < 0 || index >= q.list.Size() || (i > 0 && index <= indexes[i-1]) {
+				return data, badRecordError("log record deletes an invalid index")
+			}
+		}
+		for i := len(indexes) - 1; i >= 0; i-- {
+			q.list.RemoveAt(indexes[i])
+		}
+		return data, nil
+	default:
+		return data, badRecordError("unknown log record")
+	}
+}
+
+func (q *Deque[T]) checkWritable() error {
+	if q.closed {
+		return ClosedError("can't change a closed deque")
+	}
+	return q.err
+}
+
+func (q *Deque[T]) sync() error {
+	if err := q.log.Sync(); err != nil {
+		q.err = err
+		return err
+	}
+	q.unsynced = 0
+	return nil
+}
+
+// compact writes a snapshot of generation gen+1 holding every item, then
+// switches to a new log for that generation and removes the old one.  A crash
+// part way through leaves either the old snapshot and log or the new
+// snapshot, whose log is created on open if it doesn't exist.  The caller
+// must hold mu.
+func (q *Deque[T]) compact() error {
+	gen := q.gen + 1
+	var header [16]byte
+	binary.LittleEndian.PutUint64(header[0:], gen)
+	binary.LittleEndian.PutUint64(header[8:], uint64(q.list.Size()))
+	buf := appendRecord(nil, header[:])
+	for data := range q.list.Values() {
+		encoded, err := json.Marshal(data)
+		if err != nil {
+			q.err = err
+			return err
+		}
+		buf = appendRecord(buf, encoded)
+	}
+	if err := writeFileAtomic(filepath.Join(q.dir, snapshotName), buf); err != nil {
+		q.err = err
+		return err
+	}
+	log, err := q.openLog(gen)
+	if err != nil {
+		q.err = err
+		return err
+	}
+	old := q.log
+	q.log, q.gen, q.records, q.unsynced = log, gen, 0, 0
+	old.Close()
+	q.removeStaleFiles()
+	return nil
+}
+
+// loadSnapshot reads the items and generation from the snapshot, if there is
+// one
+func (q *Deque[T]) loadSnapshot() error {
+	buf, err := os.ReadFile(filepath.Join(q.dir, snapshotName))
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	count := -1
+	valid := readRecords(buf, func(payload []byte) error {
+		if count < 0 {
+			if len(payload) != 16 {
+				return CorruptSnapshotError("invalid snapshot header")
+			}
+			q.gen = binary.LittleEndian.Uint64(payload[0:])
+			count = int(binary.LittleEndian.Uint64(payload[8:]))
+			return nil
+		}
+		var data T
+		if err := json.Unmarshal(payload, &data); err != nil {
+			return err
+		}
+		q.list.PushTail(data)
+		return nil
+	})
+	if valid != len(buf) || count != q.list.Size() {
+		return CorruptSnapshotError("snapshot is incomplete or damaged")
+	}
+	q.recovery.SnapshotItems = count
+	return nil
+}
+
+// replayLog applies the records in the log for the snapshot's generation,
+// cuts off any damaged tail and opens the log for appending
+func (q *Deque[T]) replayLog() error {
+	path := filepath.Join(q.dir, logName(q.gen))
+	buf, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	valid := readRecords(buf, func(payload []byte) error {
+		if _, err := q.apply(payload); err != nil {
+			return err
+		}
+		q.records++
+		return nil
+	})
+	log, err := q.openLog(q.gen)
+	if err != nil {
+		return err
+	}
+	if valid < len(buf) {
+		if err = log.Truncate(int64(valid)); err == nil {
+			err = log.Sync()
+		}
+		if err != nil {
+			log.Close()
+			return err
+		}
+	}
+	q.log = log
+	q.recovery.Records = q.records
+	q.recovery.DiscardedBytes = int64(len(buf) - valid)
+	return nil
+}
+
+// openLog opens the log for generation gen for appending, creating it if it
+// doesn't exist
+func (q *Deque[T]) openLog(gen uint64) (*os.File, error) {
+	log, err := os.OpenFile(filepath.Join(q.dir, logName(gen)), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err = syncDir(q.dir); err != nil {
+		log.Close()
+		return nil, err
+	}
+	return log, nil
+}
+
+// removeStaleFiles removes logs from older generations and any snapshot left
+// half written by a crash.  Failures are ignored since the files are never
+// read again.
+func (q *Deque[T]) removeStaleFiles() {
+	current := logName(q.gen)
+	logs, _ := filepath.Glob(filepath.Join(q.dir, logPattern))
+	for _, path := range logs {
+		if filepath.Base(path) != current {
+			os.Remove(path)
+		}
+	}
+	os.Remove(filepath.Join(q.dir, snapshotName+".tmp"))
+}
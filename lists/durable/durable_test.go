@@ -0,0 +1,329 @@
+package durable
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/suicidejack/go-various/lists"
+	"github.com/suicidejack/go-various/lists/liststest"
+)
+
+func open(t *testing.T, dir string, opts ...Option) *Deque[int] {
+	q, err := Open[int](dir, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return q
+}
+
+func logPath(q *Deque[int]) string {
+	return filepath.Join(q.dir, logName(q.gen))
+}
+
+func TestConformance(t *testing.T) {
+	newDeque := func() *Deque[int] {
+		dir, err := os.MkdirTemp(t.TempDir(), "deque")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return open(t, dir, WithSyncEvery(0))
+	}
+	liststest.RunQueue(t, func() lists.Queue[int] { return newDeque() })
+	liststest.RunStack(t, func() lists.Stack[int] { return newDeque() })
+}
+
+func TestReopen(t *testing.T) {
+	dir := t.TempDir()
+	q := open(t, dir)
+	for i := 0; i < 5; i++ {
+		q.PushTail(i)
+	}
+	q.PushHead(-1)
+	data, err := q.PopTail()
+	assert.NoError(t, err)
+	assert.Equal(t, 4, data)
+	data, err = q.PopHead()
+	assert.NoError(t, err)
+	assert.Equal(t, -1, data)
+	assert.Equal(t, 2, q.Delete(0, func(data int) bool { return data%2 == 1 }))
+	assert.Equal(t, []int{0, 2}, slices.Collect(q.Values()))
+	assert.NoError(t, q.Close())
+
+	q = open(t, dir)
+	defer q.Close()
+	assert.Equal(t, []int{0, 2}, slices.Collect(q.Values()))
+	assert.Equal(t, Recovery{Records: 9}, q.Recovery())
+	q.PushTail(3)
+	assert.Equal(t, 3, q.Size())
+}
+
+func TestDeleteLimit(t *testing.T) {
+	dir := t.TempDir()
+	q := open(t, dir)
+	for i := 0; i < 6; i++ {
+		q.PushTail(i)
+	}
+	assert.Equal(t, 2, q.Delete(2, func(data int) bool { return data > 1 }))
+	assert.Equal(t, 0, q.Delete(1, func(data int) bool { return data > 10 }))
+	q.Close()
+	q = open(t, dir)
+	defer q.Close()
+	assert.Equal(t, []int{0, 1, 4, 5}, slices.Collect(q.Values()))
+}
+
+func TestTruncatedLog(t *testing.T) {
+	dir := t.TempDir()
+	q := open(t, dir)
+	q.PushTail(1)
+	q.PushTail(2)
+	q.PushTail(300)
+	path := logPath(q)
+	q.Close()
+
+	// cut the last record short, as a crash while writing it would
+	info, _ := os.Stat(path)
+	assert.NoError(t, os.Truncate(path, info.Size()-2))
+	q = open(t, dir)
+	assert.Equal(t, []int{1, 2}, slices.Collect(q.Values()))
+	recovery := q.Recovery()
+	assert.Equal(t, 2, recovery.Records)
+	assert.True(t, recovery.DiscardedBytes > 0)
+
+	// the damaged tail is gone, so new records are read back after reopening
+	q.PushTail(4)
+	q.Close()
+	q = open(t, dir)
+	defer q.Close()
+	assert.Equal(t, []int{1, 2, 4}, slices.Collect(q.Values()))
+	assert.Equal(t, int64(0), q.Recovery().DiscardedBytes)
+}
+
+func TestTruncatedHeader(t *testing.T) {
+	dir := t.TempDir()
+	q := open(t, dir)
+	q.PushTail(1)
+	path := logPath(q)
+	q.Close()
+
+	f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	f.Write([]byte{5, 0, 0})
+	f.Close()
+	q = open(t, dir)
+	defer q.Close()
+	assert.Equal(t, []int{1}, slices.Collect(q.Values()))
+	assert.Equal(t, int64(3), q.Recovery().DiscardedBytes)
+}
+
+func TestCorruptedLog(t *testing.T) {
+	dir := t.TempDir()
+	q := open(t, dir)
+	for i := 0; i < 4; i++ {
+		q.PushTail(i)
+	}
+	path := logPath(q)
+	q.Close()
+	buf, _ := os.ReadFile(path)
+	record := len(buf) / 4
+
+	// damage the last record's payload
+	buf[len(buf)-1] ^= 0xff
+	assert.NoError(t, os.WriteFile(path, buf, 0o644))
+	q = open(t, dir)
+	assert.Equal(t, []int{0, 1, 2}, slices.Collect(q.Values()))
+	assert.Equal(t, int64(record), q.Recovery().DiscardedBytes)
+	q.Close()
+
+	// damage in the middle loses everything after it too, since the records
+	// that follow may depend on the lost one
+	buf, _ = os.ReadFile(path)
+	buf[record+recordHeaderSize] ^= 0xff
+	assert.NoError(t, os.WriteFile(path, buf, 0o644))
+	q = open(t, dir)
+	defer q.Close()
+	assert.Equal(t, []int{0}, slices.Collect(q.Values()))
+}
+
+func TestImpossibleRecord(t *testing.T) {
+	// a record with a valid checksum that pops from an empty deque
+	dir := t.TempDir()
+	buf := appendRecord(nil, []byte{opPushTail, '7'})
+	buf = appendRecord(buf, []byte{opPopHead})
+	buf = appendRecord(buf, []byte{opPopHead})
+	buf = appendRecord(buf, []byte{opPushTail, '8'})
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, logName(0)), buf, 0o644))
+	q := open(t, dir)
+	defer q.Close()
+	assert.True(t, q.IsEmpty())
+	assert.Equal(t, 2, q.Recovery().Records)
+}
+
+func TestCompaction(t *testing.T) {
+	dir := t.TempDir()
+	q := open(t, dir, WithCompactEvery(10))
+	for i := 0; i < 25; i++ {
+		q.PushTail(i)
+	}
+	for i := 0; i < 5; i++ {
+		q.PopHead()
+	}
+	assert.Equal(t, 0, q.records, "the log should have been compacted")
+	assert.Equal(t, uint64(3), q.gen)
+	logs, _ := filepath.Glob(filepath.Join(dir, logPattern))
+	assert.Equal(t, []string{logPath(q)}, logs)
+	q.PushTail(25)
+	q.Close()
+
+	q = open(t, dir)
+	defer q.Close()
+	expected := make([]int, 0, 21)
+	for i := 5; i <= 25; i++ {
+		expected = append(expected, i)
+	}
+	assert.Equal(t, expected, slices.Collect(q.Values()))
+	assert.Equal(t, Recovery{SnapshotItems: 20, Records: 1}, q.Recovery())
+}
+
+func TestCrashDuringCompaction(t *testing.T) {
+	dir := t.TempDir()
+	q := open(t, dir, WithCompactEvery(0))
+	q.PushTail(1)
+	q.PushTail(2)
+	oldLog, _ := os.ReadFile(logPath(q))
+	oldPath := logPath(q)
+	assert.NoError(t, q.Compact())
+	q.Close()
+
+	// put back the old log and a half-written snapshot, as if the crash came
+	// after the new snapshot was renamed into place but before the old log
+	// was removed
+	assert.NoError(t, os.WriteFile(oldPath, oldLog, 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, snapshotName+".tmp"), []byte("junk"), 0o644))
+	q = open(t, dir)
+	assert.Equal(t, []int{1, 2}, slices.Collect(q.Values()), "the old log must not be replayed again")
+	q.Close()
+	_, err := os.Stat(oldPath)
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dir, snapshotName+".tmp"))
+	assert.True(t, os.IsNotExist(err))
+
+	// and as if it came before the new log was created
+	assert.NoError(t, os.Remove(logPath(q)))
+	q = open(t, dir)
+	defer q.Close()
+	assert.Equal(t, []int{1, 2}, slices.Collect(q.Values()))
+	q.PushTail(3)
+	assert.Equal(t, 3, q.Size())
+}
+
+func TestCorruptSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	q := open(t, dir)
+	q.PushTail(1)
+	q.Compact()
+	q.Close()
+	path := filepath.Join(dir, snapshotName)
+	buf, _ := os.ReadFile(path)
+	assert.NoError(t, os.WriteFile(path, buf[:len(buf)-1], 0o644))
+	_, err := Open[int](dir)
+	assert.IsType(t, CorruptSnapshotError(""), err)
+}
+
+func TestClosed(t *testing.T) {
+	q := open(t, t.TempDir())
+	q.PushTail(1)
+	assert.NoError(t, q.Close())
+	assert.IsType(t, ClosedError(""), q.Close())
+	assert.IsType(t, ClosedError(""), q.TryPushTail(2))
+	_, err := q.PopHead()
+	assert.IsType(t, ClosedError(""), err)
+	assert.IsType(t, ClosedError(""), q.Sync())
+	assert.Equal(t, []int{1}, slices.Collect(q.Values()), "items stay readable")
+}
+
+func TestWriteErrorIsSticky(t *testing.T) {
+	q := open(t, t.TempDir())
+	q.PushTail(1)
+	q.log.Close()
+
+	q.PushTail(2)
+	assert.Error(t, q.Err())
+	assert.Equal(t, []int{1}, slices.Collect(q.Values()), "a change that wasn't logged mustn't be made")
+	_, err := q.PopHead()
+	assert.True(t, errors.Is(err, q.Err()))
+	assert.Equal(t, 0, q.Delete(0, func(int) bool { return true }))
+	assert.Equal(t, 1, q.Size())
+}
+
+func TestEmpty(t *testing.T) {
+	q := open(t, t.TempDir())
+	defer q.Close()
+	_, err := q.PopTail()
+	assert.IsType(t, lists.EmptyListError(""), err)
+	assert.Equal(t, 0, q.records, "failed pops aren't logged")
+}
+
+func TestSyncEvery(t *testing.T) {
+	dir := t.TempDir()
+	q := open(t, dir, WithSyncEvery(3))
+	q.PushTail(1)
+	q.PushTail(2)
+	assert.Equal(t, 2, q.unsynced)
+	q.PushTail(3)
+	assert.Equal(t, 0, q.unsynced)
+	q.PushTail(4)
+	assert.NoError(t, q.Sync())
+	assert.Equal(t, 0, q.unsynced)
+	q.Close()
+	q = open(t, dir)
+	defer q.Close()
+	assert.Equal(t, 4, q.Size())
+}
+
+func TestStructItems(t *testing.T) {
+	type job struct {
+		ID   int
+		Name string
+	}
+	dir := t.TempDir()
+	q, err := Open[job](dir)
+	assert.NoError(t, err)
+	q.PushTail(job{1, "a"})
+	q.PushHead(job{2, "b"})
+	q.Compact()
+	q.PushTail(job{3, "c"})
+	q.Close()
+
+	q, err = Open[job](dir)
+	assert.NoError(t, err)
+	defer q.Close()
+	assert.Equal(t, []job{{2, "b"}, {1, "a"}, {3, "c"}}, slices.Collect(q.Values()))
+}
+
+// oneWay encodes to JSON that can't be decoded back into a oneWay
+type oneWay struct{}
+
+func (oneWay) MarshalJSON() ([]byte, error) {
+	return []byte(`"one way"`), nil
+}
+
+func TestPushKeepsData(t *testing.T) {
+	dir := t.TempDir()
+	q, err := Open[any](dir)
+	assert.NoError(t, err)
+	assert.NoError(t, q.TryPushTail(1))
+	assert.Exactly(t, []any{1}, slices.Collect(q.Values()), "the pushed value, not its decoded copy, should be kept")
+	q.Close()
+
+	w, err := Open[oneWay](t.TempDir())
+	assert.NoError(t, err)
+	defer w.Close()
+	assert.Error(t, w.TryPushTail(oneWay{}))
+	assert.Error(t, w.PushHead(oneWay{}))
+	assert.True(t, w.IsEmpty())
+	assert.NoError(t, w.Err(), "a value that can't be logged shouldn't break the deque")
+	assert.Equal(t, 0, w.records)
+}
@@ -0,0 +1,37 @@
+package durable
+
+import "fmt"
+
+// ClosedError indicates that a Deque can't be used because it has been closed
+type ClosedError string
+
+func (e ClosedError) Error() string {
+	return fmt.Sprintf("durable: %s", string(e))
+}
+
+// CorruptSnapshotError indicates that the snapshot file can't be read.
+// Snapshots are written to a temporary file and renamed into place, so this
+// means the file was damaged after it was written.
+type CorruptSnapshotError string
+
+func (e CorruptSnapshotError) Error() string {
+	return fmt.Sprintf("durable: %s", string(e))
+}
+
+// CorruptSegmentError indicates that a SegmentQueue found a damaged record
+// while reading an item.  Records are checked when the queue is opened, so
+// this means a segment file was damaged while the queue was open.
+type CorruptSegmentError string
+
+func (e CorruptSegmentError) Error() string {
+	return fmt.Sprintf("durable: %s", string(e))
+}
+
+// badRecordError indicates a log record that passes its checksum but doesn't
+// describe a possible change.  Open treats it like a damaged record and
+// discards it and the rest of the log.
+type badRecordError string
+
+func (e badRecordError) Error() string {
+	return fmt.Sprintf("durable: %s", string(e))
+}
@@ -0,0 +1,111 @@
+package durable
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+)
+
+// Every change to a Deque is appended to the log as one record, and
+// snapshots are a sequence of records too.  A record is the length and the
+// CRC-32C of its payload, each a little-endian uint32, followed by the
+// payload.  The first byte of a log record's payload is one of the op
+// constants and the rest is the JSON encoding of the item pushed or, for
+// opDelete, of the indexes removed.
+const (
+	opPushHead byte = iota + 1
+	opPushTail
+	opPopHead
+	opPopTail
+	opDelete
+)
+
+const (
+	recordHeaderSize = 8
+	snapshotName     = "snapshot"
+	logPattern       = "wal-*.log"
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// appendRecord appends payload to buf as a record
+func appendRecord(buf, payload []byte) []byte {
+	var header [recordHeaderSize]byte
+	binary.LittleEndian.PutUint32(header[0:], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(header[4:], crc32.Checksum(payload, crcTable))
+	return append(append(buf, header[:]...), payload...)
+}
+
+// readRecord returns the payload of the record at the start of data, or
+// false if the record is cut short or fails its checksum
+func readRecord(data []byte) (payload []byte, ok bool) {
+	if len(data) < recordHeaderSize {
+		return nil, false
+	}
+	size := binary.LittleEndian.Uint32(data)
+	sum := binary.LittleEndian.Uint32(data[4:])
+	if uint64(size) > uint64(len(data)-recordHeaderSize) {
+		return nil, false
+	}
+	payload = data[recordHeaderSize : recordHeaderSize+int(size)]
+	return payload, crc32.Checksum(payload, crcTable) == sum
+}
+
+// readRecords calls f with the payload of each record in data in turn, and
+// returns the length of the prefix of data holding complete, intact records
+// that f accepted.  It stops at the first record that is cut short, fails its
+// checksum or makes f return an error.
+func readRecords(data []byte, f func(payload []byte) error) (valid int) {
+	for {
+		payload, ok := readRecord(data[valid:])
+		if !ok || f(payload) != nil {
+			return
+		}
+		valid += recordHeaderSize + len(payload)
+	}
+}
+
+// logName is the name of the log that follows the snapshot of generation gen
+func logName(gen uint64) string {
+	return fmt.Sprintf("wal-%016x.log", gen)
+}
+
+// writeFileAtomic replaces the file at path with data so that a crash leaves
+// either the old file or the new one, never a mix
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(data); err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return syncDir(filepath.Dir(path))
+}
+
+// syncDir fsyncs dir so that files created, renamed or removed in it survive
+// a crash
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	err = d.Sync()
+	if closeErr := d.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
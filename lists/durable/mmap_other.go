@@ -0,0 +1,43 @@
+//go:build !unix
+
+package durable
+
+import (
+	"io"
+	"os"
+	"slices"
+)
+
+// mapFile returns a copy of up to the first size bytes of f, on systems
+// without mmap.  Unlike a mapping, the copy is cut short at the end of the
+// file and doesn't show later writes.
+func mapFile(f *os.File, size int) ([]byte, error) {
+	data := make([]byte, size)
+	n, err := f.ReadAt(data, 0)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+// unmapFile releases a view returned by mapFile
+func unmapFile(data []byte) error {
+	return nil
+}
+
+// extendFile grows data, a copy of the start of f returned by mapFile or
+// extendFile, to the first size bytes of f, on systems without mmap.  Only
+// the bytes after the end of data are read, so a segment read a record at a
+// time is only read once.  limit is ignored, since a copy doesn't show later
+// writes however far it reaches.
+func extendFile(f *os.File, data []byte, size, limit int) ([]byte, error) {
+	if len(data) >= size {
+		return data, nil
+	}
+	grown := slices.Grow(data, size-len(data))[:size]
+	n, err := f.ReadAt(grown[len(data):], int64(len(data)))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	return grown[:len(data)+n], nil
+}
@@ -0,0 +1,36 @@
+//go:build unix
+
+package durable
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapFile returns a read-only view of the first size bytes of f.  Writes to f
+// through other file descriptors show up in the view.
+func mapFile(f *os.File, size int) ([]byte, error) {
+	if size == 0 {
+		return nil, nil
+	}
+	return syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+// unmapFile releases a view returned by mapFile
+func unmapFile(data []byte) error {
+	if data == nil {
+		return nil
+	}
+	return syscall.Munmap(data)
+}
+
+// extendFile replaces data, a view of f returned by mapFile or extendFile,
+// with a view covering the first limit bytes of f, so that later growth of f
+// up to limit shows up without another call.  size, the number of bytes the
+// caller needs, is only used on systems without mmap.
+func extendFile(f *os.File, data []byte, size, limit int) ([]byte, error) {
+	if err := unmapFile(data); err != nil {
+		return nil, err
+	}
+	return mapFile(f, limit)
+}
@@ -0,0 +1,48 @@
+package durable
+
+// Option configures a Deque or SegmentQueue when it is opened
+type Option func(*options)
+
+type options struct {
+	syncEvery    int
+	compactEvery int
+	segmentSize  int
+}
+
+// WithSyncEvery fsyncs the log after every n records instead of after every
+// record.  Changes made since the last fsync can be lost if the machine
+// crashes, but not if only the process does.  n of 0 never fsyncs the log
+// except in Sync, Compact and Close.  For a SegmentQueue, the log is its tail
+// segment and its record of how far items have been read.
+func WithSyncEvery(n int) Option {
+	return func(o *options) {
+		o.syncEvery = n
+	}
+}
+
+// WithCompactEvery writes a snapshot of a Deque's items and starts a new,
+// empty log once the log holds n records, instead of the default of 1000.  n
+// of 0 only compacts when Compact is called.  It has no effect on a
+// SegmentQueue.
+func WithCompactEvery(n int) Option {
+	return func(o *options) {
+		o.compactEvery = n
+	}
+}
+
+// WithSegmentSize starts a new segment file once a SegmentQueue's tail
+// segment would grow beyond n bytes, instead of the default of 64 MiB.  An
+// item bigger than n gets a segment to itself.  It has no effect on a Deque.
+func WithSegmentSize(n int) Option {
+	return func(o *options) {
+		o.segmentSize = n
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{syncEvery: 1, compactEvery: 1000, segmentSize: 64 << 20}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,507 @@
+package durable
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/suicidejack/go-various/lists"
+)
+
+const (
+	segmentPattern = "seg-*.dat"
+	headName       = "head"
+	// a head position is the segment and offset of the next item, each a
+	// little-endian uint64, followed by their CRC-32C
+	headSize = 20
+)
+
+// SegmentQueue goroutine-safe, disk-backed FIFO queue holding items of type
+// T, for backlogs too big to keep in memory.  Items are appended as records
+// to numbered segment files of a fixed maximum size, and read back through a
+// memory-mapped view of the segment holding the front of the queue.  Only
+// that segment and the one being appended to are open at a time, and each
+// segment is deleted as soon as its last item has been read.  The position
+// of the front of the queue is saved in a small file after every PopHead.
+//
+// It implements lists.Queue, so it can replace a Doubly used as a queue.  As
+// with Deque, an error writing to disk is returned by the change that hit it
+// and recorded for Err to return, and after it every further change is
+// refused.
+//
+// If the machine crashes, items pushed since the last fsync may be lost and
+// items popped since the last fsync may be returned again after reopening,
+// so consumers should be able to handle an item more than once.
+type SegmentQueue[T any] struct {
+	mu   sync.Mutex
+	dir  string
+	opts options
+	size int
+	// headSeg is the segment holding the front of the queue and headOff is
+	// the offset of its next record
+	headSeg uint64
+	headOff int
+	// headMap is a read-only view of headSeg
+	headMap []byte
+	// headFile is where headSeg and headOff are saved
+	headFile *os.File
+	tailSeg  uint64
+	tail     *os.File
+	tailSize int
+	// ends is the length of the valid records in each segment before the
+	// tail
+	ends     map[uint64]int
+	unsynced int
+	recovery Recovery
+	// err is the first error reading or writing a file
+	err    error
+	closed bool
+}
+
+var _ lists.Queue[interface{}] = (*SegmentQueue[interface{}])(nil)
+
+// OpenSegmentQueue opens the SegmentQueue stored in dir, creating dir and an
+// empty queue if they don't exist.  Every segment is read to count the items
+// and check them against their checksums.  A cut-short or corrupted tail of a
+// segment is discarded, as described by Recovery.
+func OpenSegmentQueue[T any](dir string, opts ...Option) (*SegmentQueue[T], error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	q := &SegmentQueue[T]{dir: dir, opts: newOptions(opts), ends: map[uint64]int{}}
+	segments, err := q.segments()
+	if err != nil {
+		return nil, err
+	}
+	q.headSeg = segments[0]
+	q.tailSeg = segments[len(segments)-1]
+	q.loadHead()
+	for _, seg := range segments {
+		if seg < q.headSeg {
+			// the segment was read but not deleted before a crash
+			os.Remove(q.segmentPath(seg))
+		} else if seg < q.tailSeg {
+			if err = q.scanSegment(seg); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err = q.recoverTail(); err != nil {
+		return nil, err
+	}
+	q.headFile, err = os.OpenFile(filepath.Join(dir, headName), os.O_RDWR|os.O_CREATE, 0o644)
+	if err == nil {
+		err = q.saveHead()
+	}
+	if err != nil {
+		q.tail.Close()
+		return nil, err
+	}
+	q.recovery.Records = q.size
+	return q, nil
+}
+
+// Size of the queue
+//
+// Runtime: O(1)
+func (q *SegmentQueue[T]) Size() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.size
+}
+
+// IsEmpty returns true if the queue contains no items
+//
+// Runtime: O(1)
+func (q *SegmentQueue[T]) IsEmpty() bool {
+	return q.Size() == 0
+}
+
+// PushTail is the same as TryPushTail.  A failure to write to disk is also
+// returned by Err.
+//
+// Runtime: O(1), plus writing the record
+func (q *SegmentQueue[T]) PushTail(data T) error {
+	return q.TryPushTail(data)
+}
+
+// TryPushTail adds data to the back of the queue and returns any error
+// encoding data or writing it to disk, in which case data isn't added.
+// Returns a ClosedError if the queue has been closed.
+//
+// Runtime: O(1), plus writing the record
+func (q *SegmentQueue[T]) TryPushTail(data T) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err = q.checkWritable(); err != nil {
+		return err
+	}
+	record := appendRecord(nil, encoded)
+	if q.tailSize > 0 && q.tailSize+len(record) > q.opts.segmentSize {
+		if err = q.roll(); err != nil {
+			return err
+		}
+	}
+	if _, err = q.tail.Write(record); err != nil {
+		q.err = err
+		return err
+	}
+	q.tailSize += len(record)
+	q.size++
+	return q.written()
+}
+
+// PopHead removes data from the front of the queue.  Returns a
+// lists.EmptyListError if there are no items in the queue, a ClosedError if
+// it has been closed, or any error reading or writing the files.  If the item
+// can't be decoded as a T it is still removed, and the error is returned.
+//
+// Runtime: O(1), plus saving the new position of the front of the queue
+func (q *SegmentQueue[T]) PopHead() (data T, err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err = q.checkWritable(); err != nil {
+		return
+	}
+	if q.size == 0 {
+		return data, lists.EmptyListError("can't remove an item from an empty list")
+	}
+	payload, err := q.next()
+	if err != nil {
+		q.err = err
+		return
+	}
+	decodeErr := json.Unmarshal(payload, &data)
+	q.headOff += recordHeaderSize + len(payload)
+	q.size--
+	if q.headSeg != q.tailSeg && q.headOff >= q.ends[q.headSeg] {
+		// delete the finished segment now rather than on the next pop, which
+		// may never come
+		if err = q.advance(); err != nil {
+			q.err = err
+			return
+		}
+	}
+	if err = q.saveHead(); err == nil {
+		err = q.written()
+	}
+	if err == nil {
+		err = decodeErr
+	}
+	return
+}
+
+// Err returns the first error reading or writing a file, after which the
+// queue refuses any more changes, or nil.
+func (q *SegmentQueue[T]) Err() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.err
+}
+
+// Recovery describes what OpenSegmentQueue found on disk.  Records is the
+// number of items in the queue when it was opened.
+func (q *SegmentQueue[T]) Recovery() Recovery {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.recovery
+}
+
+// Sync fsyncs the tail segment and the position of the front of the queue,
+// making every change so far durable even with a WithSyncEvery setting that
+// would otherwise delay it.
+func (q *SegmentQueue[T]) Sync() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err := q.checkWritable(); err != nil {
+		return err
+	}
+	return q.sync()
+}
+
+// Close fsyncs and closes the files.  Returns a ClosedError if the queue was
+// already closed.
+func (q *SegmentQueue[T]) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ClosedError("queue is already closed")
+	}
+	q.closed = true
+	err := q.sync()
+	for _, closeErr := range []error{q.tail.Close(), q.headFile.Close(), unmapFile(q.headMap)} {
+		if err == nil {
+			err = closeErr
+		}
+	}
+	q.headMap = nil
+	return err
+}
+
+func (q *SegmentQueue[T]) checkWritable() error {
+	if q.closed {
+		return ClosedError("can't change a closed queue")
+	}
+	return q.err
+}
+
+// written fsyncs the files if enough changes have been made since they were
+// last synced.  The caller must hold mu.
+func (q *SegmentQueue[T]) written() error {
+	q.unsynced++
+	if q.opts.syncEvery > 0 && q.unsynced >= q.opts.syncEvery {
+		return q.sync()
+	}
+	return nil
+}
+
+func (q *SegmentQueue[T]) sync() error {
+	err := q.tail.Sync()
+	if err == nil {
+		err = q.headFile.Sync()
+	}
+	if err != nil {
+		q.err = err
+		return err
+	}
+	q.unsynced = 0
+	return nil
+}
+
+// roll finishes the tail segment and starts a new one.  The caller must hold
+// mu.
+func (q *SegmentQueue[T]) roll() error {
+	err := q.tail.Sync()
+	if closeErr := q.tail.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		q.ends[q.tailSeg] = q.tailSize
+		q.tail, err = q.openSegment(q.tailSeg + 1)
+	}
+	if err != nil {
+		q.err = err
+		return err
+	}
+	q.tailSeg++
+	q.tailSize = 0
+	return nil
+}
+
+// next returns the payload of the record at the front of a non-empty queue,
+// moving on to the next segment, and deleting the finished one, if the head
+// segment has been read to the end.  The payload points into the view of the
+// head segment, so it is only valid until the view is next replaced.  The
+// caller must hold mu.
+func (q *SegmentQueue[T]) next() ([]byte, error) {
+	for {
+		end := q.tailSize
+		if q.headSeg != q.tailSeg {
+			end = q.ends[q.headSeg]
+		}
+		if q.headOff >= end {
+			if err := q.advance(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if len(q.headMap) < end {
+			if err := q.mapHead(end); err != nil {
+				return nil, err
+			}
+		}
+		payload, ok := readRecord(q.headMap[q.headOff:end])
+		if !ok {
+			return nil, CorruptSegmentError(fmt.Sprintf("damaged record at offset %d of %s", q.headOff, segmentName(q.headSeg)))
+		}
+		return payload, nil
+	}
+}
+
+// advance deletes the head segment, which has been read to the end, and
+// moves the front of the queue to the start of the next one.  The caller
+// must hold mu.
+func (q *SegmentQueue[T]) advance() error {
+	err := unmapFile(q.headMap)
+	q.headMap = nil
+	if err == nil {
+		err = os.Remove(q.segmentPath(q.headSeg))
+	}
+	if err != nil {
+		return err
+	}
+	delete(q.ends, q.headSeg)
+	q.headSeg++
+	q.headOff = 0
+	return nil
+}
+
+// mapHead replaces the view of the head segment with one covering at least
+// its first size bytes.  A view of the tail segment may cover the whole
+// segment size, so it doesn't need replacing every time an item is pushed.
+// The caller must hold mu.
+func (q *SegmentQueue[T]) mapHead(size int) error {
+	f, err := os.Open(q.segmentPath(q.headSeg))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	limit := size
+	if q.headSeg == q.tailSeg && limit < q.opts.segmentSize {
+		limit = q.opts.segmentSize
+	}
+	q.headMap, err = extendFile(f, q.headMap, size, limit)
+	return err
+}
+
+// saveHead records the position of the front of the queue.  The caller must
+// hold mu.
+func (q *SegmentQueue[T]) saveHead() error {
+	var buf [headSize]byte
+	binary.LittleEndian.PutUint64(buf[0:], q.headSeg)
+	binary.LittleEndian.PutUint64(buf[8:], uint64(q.headOff))
+	binary.LittleEndian.PutUint32(buf[16:], crc32.Checksum(buf[:16], crcTable))
+	if _, err := q.headFile.WriteAt(buf[:], 0); err != nil {
+		q.err = err
+		return err
+	}
+	return nil
+}
+
+// loadHead reads the saved position of the front of the queue.  If there is
+// none, or it doesn't match the segments, the front of the queue is the start
+// of the first segment.
+func (q *SegmentQueue[T]) loadHead() {
+	buf, err := os.ReadFile(filepath.Join(q.dir, headName))
+	if err != nil || len(buf) != headSize || crc32.Checksum(buf[:16], crcTable) != binary.LittleEndian.Uint32(buf[16:]) {
+		return
+	}
+	seg := binary.LittleEndian.Uint64(buf[0:])
+	if seg < q.headSeg || seg > q.tailSeg {
+		return
+	}
+	q.headSeg = seg
+	q.headOff = int(binary.LittleEndian.Uint64(buf[8:]))
+}
+
+// scanSegment counts the items in a segment before the tail, from headOff if
+// it is the head segment, and records where its valid records end.
+func (q *SegmentQueue[T]) scanSegment(seg uint64) error {
+	f, err := os.Open(q.segmentPath(seg))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	data, err := mapFile(f, int(info.Size()))
+	if err != nil {
+		return err
+	}
+	defer unmapFile(data)
+	valid := readRecords(data, func([]byte) error { return nil })
+	q.ends[seg] = valid
+	q.recovery.DiscardedBytes += int64(len(data) - valid)
+	if seg == q.headSeg {
+		q.headOff = min(q.headOff, valid)
+		q.size += countRecords(data[q.headOff:valid])
+	} else {
+		q.size += countRecords(data[:valid])
+	}
+	return nil
+}
+
+// recoverTail cuts any damaged records from the end of the tail segment,
+// counts its items and opens it for appending
+func (q *SegmentQueue[T]) recoverTail() error {
+	buf, err := os.ReadFile(q.segmentPath(q.tailSeg))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	valid := readRecords(buf, func([]byte) error { return nil })
+	q.tail, err = q.openSegment(q.tailSeg)
+	if err != nil {
+		return err
+	}
+	if valid < len(buf) {
+		if err = q.tail.Truncate(int64(valid)); err == nil {
+			err = q.tail.Sync()
+		}
+		if err != nil {
+			q.tail.Close()
+			return err
+		}
+		q.recovery.DiscardedBytes += int64(len(buf) - valid)
+	}
+	q.tailSize = valid
+	if q.headSeg == q.tailSeg {
+		q.headOff = min(q.headOff, valid)
+		q.size += countRecords(buf[q.headOff:valid])
+	} else {
+		q.size += countRecords(buf[:valid])
+	}
+	return nil
+}
+
+// segments returns the numbers of the segments in the directory in order,
+// or just 0 if there are none yet
+func (q *SegmentQueue[T]) segments() ([]uint64, error) {
+	paths, err := filepath.Glob(filepath.Join(q.dir, segmentPattern))
+	if err != nil {
+		return nil, err
+	}
+	var segments []uint64
+	for _, path := range paths {
+		var seg uint64
+		if _, err := fmt.Sscanf(filepath.Base(path), "seg-%016x.dat", &seg); err == nil {
+			segments = append(segments, seg)
+		}
+	}
+	if len(segments) == 0 {
+		return []uint64{0}, nil
+	}
+	sort.Slice(segments, func(i, j int) bool { return segments[i] < segments[j] })
+	return segments, nil
+}
+
+// openSegment opens a segment for appending, creating it if it doesn't exist
+func (q *SegmentQueue[T]) openSegment(seg uint64) (*os.File, error) {
+	f, err := os.OpenFile(q.segmentPath(seg), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err = syncDir(q.dir); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+func (q *SegmentQueue[T]) segmentPath(seg uint64) string {
+	return filepath.Join(q.dir, segmentName(seg))
+}
+
+func segmentName(seg uint64) string {
+	return fmt.Sprintf("seg-%016x.dat", seg)
+}
+
+// countRecords returns the number of records in data, which must hold only
+// complete records
+func countRecords(data []byte) (n int) {
+	readRecords(data, func([]byte) error {
+		n++
+		return nil
+	})
+	return
+}
@@ -0,0 +1,216 @@
+package durable
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/suicidejack/go-various/lists"
+	"github.com/suicidejack/go-various/lists/liststest"
+)
+
+func openSegments(t *testing.T, dir string, opts ...Option) *SegmentQueue[int] {
+	q, err := OpenSegmentQueue[int](dir, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return q
+}
+
+func popAll(t *testing.T, q *SegmentQueue[int]) (items []int) {
+	for !q.IsEmpty() {
+		data, err := q.PopHead()
+		assert.NoError(t, err)
+		items = append(items, data)
+	}
+	return
+}
+
+func segmentFiles(dir string) []string {
+	paths, _ := filepath.Glob(filepath.Join(dir, segmentPattern))
+	return paths
+}
+
+func TestSegmentQueueConformance(t *testing.T) {
+	liststest.RunQueue(t, func() lists.Queue[int] {
+		dir, err := os.MkdirTemp(t.TempDir(), "queue")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return openSegments(t, dir, WithSyncEvery(0), WithSegmentSize(256))
+	})
+}
+
+func TestSegmentQueueRollsAndDeletesSegments(t *testing.T) {
+	dir := t.TempDir()
+	q := openSegments(t, dir, WithSegmentSize(64))
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, q.TryPushTail(i))
+	}
+	assert.Equal(t, 100, q.Size())
+	before := len(segmentFiles(dir))
+	assert.True(t, before > 10, "items should be spread over many segments")
+
+	for i := 0; i < 50; i++ {
+		data, err := q.PopHead()
+		assert.NoError(t, err)
+		assert.Equal(t, i, data)
+	}
+	assert.True(t, len(segmentFiles(dir)) < before, "segments should be deleted once read")
+	assert.NoError(t, q.Close())
+
+	q = openSegments(t, dir, WithSegmentSize(64))
+	defer q.Close()
+	assert.Equal(t, 50, q.Recovery().Records)
+	items := popAll(t, q)
+	assert.Equal(t, 50, len(items))
+	assert.Equal(t, 50, items[0])
+	assert.Equal(t, 99, items[49])
+	assert.Equal(t, 1, len(segmentFiles(dir)), "only the tail segment should remain")
+}
+
+func TestSegmentQueueDeletesSegmentOnLastPop(t *testing.T) {
+	dir := t.TempDir()
+	q := openSegments(t, dir, WithSegmentSize(64))
+	defer q.Close()
+	for i := 0; i < 20; i++ {
+		assert.NoError(t, q.TryPushTail(i))
+	}
+	for !q.IsEmpty() {
+		seg := q.headSeg
+		_, err := q.PopHead()
+		assert.NoError(t, err)
+		if q.headSeg != seg {
+			assert.Nil(t, q.headMap, "a finished segment should be unmapped")
+		}
+		if q.headSeg != q.tailSeg {
+			assert.Less(t, q.headOff, q.ends[q.headSeg], "a finished segment should be left straight away")
+		}
+		assert.Equal(t, int(q.tailSeg-q.headSeg)+1, len(segmentFiles(dir)), "only unfinished segments should remain")
+	}
+}
+
+func TestSegmentQueueInterleaved(t *testing.T) {
+	// the head and tail share a segment, so pops must see items pushed after
+	// the segment was mapped
+	dir := t.TempDir()
+	q := openSegments(t, dir, WithSegmentSize(1<<10))
+	defer q.Close()
+	next := 0
+	for i := 0; i < 300; i++ {
+		q.PushTail(i)
+		if i%3 == 2 {
+			for j := 0; j < 2; j++ {
+				data, err := q.PopHead()
+				assert.NoError(t, err)
+				assert.Equal(t, next, data)
+				next++
+			}
+		}
+	}
+	assert.NoError(t, q.Err())
+	assert.Equal(t, 100, q.Size())
+}
+
+func TestSegmentQueueLargeItem(t *testing.T) {
+	dir := t.TempDir()
+	q, err := OpenSegmentQueue[string](dir, WithSegmentSize(32))
+	assert.NoError(t, err)
+	big := strings.Repeat("x", 100)
+	q.PushTail("a")
+	q.PushTail(big)
+	q.PushTail("b")
+	assert.NoError(t, q.Close())
+
+	q, err = OpenSegmentQueue[string](dir, WithSegmentSize(32))
+	assert.NoError(t, err)
+	defer q.Close()
+	for _, expected := range []string{"a", big, "b"} {
+		data, err := q.PopHead()
+		assert.NoError(t, err)
+		assert.Equal(t, expected, data)
+	}
+}
+
+func TestSegmentQueueTruncatedTail(t *testing.T) {
+	dir := t.TempDir()
+	q := openSegments(t, dir)
+	q.PushTail(1)
+	q.PushTail(2)
+	q.PushTail(3)
+	path := q.segmentPath(q.tailSeg)
+	q.Close()
+
+	info, _ := os.Stat(path)
+	assert.NoError(t, os.Truncate(path, info.Size()-1))
+	q = openSegments(t, dir)
+	assert.True(t, q.Recovery().DiscardedBytes > 0)
+	q.PushTail(4)
+	assert.Equal(t, []int{1, 2, 4}, popAll(t, q))
+	q.Close()
+}
+
+func TestSegmentQueueCorruptedRecord(t *testing.T) {
+	dir := t.TempDir()
+	q := openSegments(t, dir)
+	q.PushTail(1)
+	q.PushTail(2)
+	path := q.segmentPath(q.tailSeg)
+	q.Close()
+
+	buf, _ := os.ReadFile(path)
+	buf[len(buf)-1] ^= 0xff
+	assert.NoError(t, os.WriteFile(path, buf, 0o644))
+	q = openSegments(t, dir)
+	defer q.Close()
+	assert.Equal(t, []int{1}, popAll(t, q))
+}
+
+func TestSegmentQueueResumesFromHead(t *testing.T) {
+	dir := t.TempDir()
+	q := openSegments(t, dir, WithSegmentSize(64))
+	for i := 0; i < 20; i++ {
+		q.PushTail(i)
+	}
+	for i := 0; i < 7; i++ {
+		q.PopHead()
+	}
+	q.Close()
+
+	q = openSegments(t, dir, WithSegmentSize(64))
+	data, err := q.PopHead()
+	assert.NoError(t, err)
+	assert.Equal(t, 7, data)
+	assert.Equal(t, 12, q.Size())
+	q.Close()
+
+	// without a valid saved position, every item still on disk is read again
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, headName), []byte("damaged"), 0o644))
+	q = openSegments(t, dir, WithSegmentSize(64))
+	defer q.Close()
+	items := popAll(t, q)
+	assert.True(t, len(items) >= 12)
+	assert.Equal(t, 19, items[len(items)-1])
+}
+
+func TestSegmentQueueErrors(t *testing.T) {
+	q := openSegments(t, t.TempDir())
+	_, err := q.PopHead()
+	assert.IsType(t, lists.EmptyListError(""), err)
+	q.PushTail(1)
+	assert.NoError(t, q.Sync())
+	assert.NoError(t, q.Close())
+	assert.IsType(t, ClosedError(""), q.Close())
+	assert.IsType(t, ClosedError(""), q.TryPushTail(2))
+	_, err = q.PopHead()
+	assert.IsType(t, ClosedError(""), err)
+
+	q = openSegments(t, t.TempDir())
+	q.tail.Close()
+	q.PushTail(1)
+	assert.Error(t, q.Err())
+	assert.Equal(t, 0, q.Size())
+	q.headFile.Close()
+}
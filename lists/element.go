@@ -0,0 +1,373 @@
+package lists
+
+import (
+	"context"
+	"runtime"
+)
+
+// Element is an opaque handle to an item in a Doubly or a LockCoupled.  It is
+// returned by PushHeadElement, PushTailElement, InsertBefore and InsertAfter
+// and lets the item be removed or relinked in O(1) without scanning the list.
+// An Element can only be used with the list its item is in, which changes if
+// the item is moved by Concat, SpliceAt, SplitAt or SplitWhere, and stops
+// being valid once its item is removed.
+type Element[T any] struct {
+	node    *doublyNode[T]
+	coupled *coupledNode[T]
+}
+
+// PushHeadElement adds data to the front of the list and returns a handle to
+// it.  Bounded lists apply their OverflowPolicy like PushHead, returning
+// any error it returns, and a nil Element without an error if the Drop
+// policy discards the item.  Returns a ClosedListError if the list has been
+// closed.
+//
+// Runtime: O(1)
+func (d *Doubly[T]) PushHeadElement(data T) (e *Element[T], err error) {
+	d.rwLock.Lock()
+	defer d.rwLock.Unlock()
+	node, err := d.push(context.Background(), true, data, true)
+	if node == nil {
+		return nil, err
+	}
+	return &Element[T]{node: node}, nil
+}
+
+// PushTailElement adds data to the back of the list and returns a handle to
+// it.  Bounded lists apply their OverflowPolicy like PushTail, returning
+// any error it returns, and a nil Element without an error if the Drop
+// policy discards the item.  Returns a ClosedListError if the list has been
+// closed.
+//
+// Runtime: O(1)
+func (d *Doubly[T]) PushTailElement(data T) (e *Element[T], err error) {
+	d.rwLock.Lock()
+	defer d.rwLock.Unlock()
+	node, err := d.push(context.Background(), true, data, false)
+	if node == nil {
+		return nil, err
+	}
+	return &Element[T]{node: node}, nil
+}
+
+// Value returns the data referenced by e.  Returns an InvalidElementError if
+// e is not in the list.
+//
+// Runtime: O(1)
+func (d *Doubly[T]) Value(e *Element[T]) (data T, err error) {
+	d.rwLock.RLock()
+	defer d.rwLock.RUnlock()
+	if err = d.checkElement(e); err != nil {
+		return
+	}
+	return e.node.Data, nil
+}
+
+// Remove removes the item referenced by e from the list and returns its data.
+// e is no longer valid afterwards.  Returns an InvalidElementError if e is not
+// in the list.
+//
+// Runtime: O(1)
+func (d *Doubly[T]) Remove(e *Element[T]) (data T, err error) {
+	d.rwLock.Lock()
+	defer d.rwLock.Unlock()
+	if err = d.checkElement(e); err != nil {
+		return
+	}
+	return d.remove(e.node), nil
+}
+
+// MoveToHead moves the item referenced by e to the front of the list.
+// Returns an InvalidElementError if e is not in the list.
+//
+// Runtime: O(1)
+func (d *Doubly[T]) MoveToHead(e *Element[T]) error {
+	d.rwLock.Lock()
+	defer d.rwLock.Unlock()
+	if err := d.checkElement(e); err != nil {
+		return err
+	}
+	if e.node != d.head {
+		d.unsplice(e.node)
+		d.splice(e.node, nil)
+	}
+	return nil
+}
+
+// MoveToTail moves the item referenced by e to the back of the list.  Returns
+// an InvalidElementError if e is not in the list.
+//
+// Runtime: O(1)
+func (d *Doubly[T]) MoveToTail(e *Element[T]) error {
+	d.rwLock.Lock()
+	defer d.rwLock.Unlock()
+	if err := d.checkElement(e); err != nil {
+		return err
+	}
+	if e.node != d.tail {
+		d.unsplice(e.node)
+		d.splice(e.node, d.tail)
+	}
+	return nil
+}
+
+// InsertBefore adds data to the list immediately in front of the item
+// referenced by e and returns a handle to it.  Returns an InvalidElementError
+// if e is not in the list.  On a bounded list that is full, the Drop policy
+// discards data and returns a nil Element and every other policy returns a
+// CapacityExceededError.
+//
+// Runtime: O(1)
+func (d *Doubly[T]) InsertBefore(e *Element[T], data T) (*Element[T], error) {
+	d.rwLock.Lock()
+	defer d.rwLock.Unlock()
+	if err := d.checkElement(e); err != nil {
+		return nil, err
+	}
+	if ok, err := d.bounds.admit(context.Background(), false, &d.size, nil); !ok {
+		return nil, err
+	}
+	node := &doublyNode[T]{Data: data}
+	d.link(node, e.node.Prev)
+	return &Element[T]{node: node}, nil
+}
+
+// InsertAfter adds data to the list immediately behind the item referenced by
+// e and returns a handle to it.  Returns an InvalidElementError if e is not in
+// the list.  Full bounded lists are handled like InsertBefore.
+//
+// Runtime: O(1)
+func (d *Doubly[T]) InsertAfter(e *Element[T], data T) (*Element[T], error) {
+	d.rwLock.Lock()
+	defer d.rwLock.Unlock()
+	if err := d.checkElement(e); err != nil {
+		return nil, err
+	}
+	if ok, err := d.bounds.admit(context.Background(), false, &d.size, nil); !ok {
+		return nil, err
+	}
+	node := &doublyNode[T]{Data: data}
+	d.link(node, e.node)
+	return &Element[T]{node: node}, nil
+}
+
+// checkElement returns an InvalidElementError if e does not reference an item
+// in the list.  The caller must hold the read or write lock.
+func (d *Doubly[T]) checkElement(e *Element[T]) error {
+	if e == nil || e.node == nil && e.coupled == nil {
+		return InvalidElementError("element is nil")
+	}
+	if e.node == nil {
+		return InvalidElementError("element belongs to a different list")
+	}
+	switch e.node.list() {
+	case d:
+		return nil
+	case nil:
+		return InvalidElementError("element has been removed from its list")
+	default:
+		return InvalidElementError("element belongs to a different list")
+	}
+}
+
+// PushHeadElement adds data to the front of the list and returns a handle to
+// it.  Bounded lists apply their OverflowPolicy like PushHead, returning any
+// error it returns, and a nil Element without an error if the Drop policy
+// discards the item.  Returns a ClosedListError if the list has been closed.
+//
+// Runtime: O(1)
+func (l *LockCoupled[T]) PushHeadElement(data T) (e *Element[T], err error) {
+	node, err := l.push(context.Background(), true, data, true)
+	if node == nil {
+		return nil, err
+	}
+	return &Element[T]{coupled: node}, nil
+}
+
+// PushTailElement adds data to the back of the list and returns a handle to
+// it.  Bounded lists apply their OverflowPolicy like PushTail, returning any
+// error it returns, and a nil Element without an error if the Drop policy
+// discards the item.  Returns a ClosedListError if the list has been closed.
+//
+// Runtime: O(1)
+func (l *LockCoupled[T]) PushTailElement(data T) (e *Element[T], err error) {
+	node, err := l.push(context.Background(), true, data, false)
+	if node == nil {
+		return nil, err
+	}
+	return &Element[T]{coupled: node}, nil
+}
+
+// Value returns the data referenced by e.  Returns an InvalidElementError if
+// e is not in the list.  Only the item's node is locked.
+//
+// Runtime: O(1)
+func (l *LockCoupled[T]) Value(e *Element[T]) (data T, err error) {
+	if err = l.checkElement(e); err != nil {
+		return
+	}
+	e.coupled.mu.Lock()
+	defer e.coupled.mu.Unlock()
+	if err = l.checkElement(e); err != nil {
+		return
+	}
+	return e.coupled.data, nil
+}
+
+// Remove removes the item referenced by e from the list and returns its data.
+// e is no longer valid afterwards.  Returns an InvalidElementError if e is not
+// in the list.  Only the item's node and its neighbours are locked, and then
+// the gate.
+//
+// Runtime: O(1), retrying if another goroutine changes the neighbours first
+func (l *LockCoupled[T]) Remove(e *Element[T]) (data T, err error) {
+	pred, succ, err := l.lockElement(e)
+	if err != nil {
+		return
+	}
+	data = l.remove(e.coupled, pred, succ)
+	succ.mu.Unlock()
+	e.coupled.mu.Unlock()
+	pred.mu.Unlock()
+	l.removed(1)
+	return data, nil
+}
+
+// MoveToHead moves the item referenced by e to the front of the list.
+// Returns an InvalidElementError if e is not in the list.  The item is taken
+// out of the list and relinked while holding the gate, so a goroutine that
+// doesn't take the gate, such as PopHead or a scan, can briefly miss it.
+//
+// Runtime: O(1), retrying if another goroutine changes the neighbours first
+func (l *LockCoupled[T]) MoveToHead(e *Element[T]) error {
+	return l.move(e, true)
+}
+
+// MoveToTail moves the item referenced by e to the back of the list.  Returns
+// an InvalidElementError if e is not in the list.  The item can briefly be
+// missed by other goroutines, as for MoveToHead.
+//
+// Runtime: O(1), retrying if another goroutine changes the neighbours first
+func (l *LockCoupled[T]) MoveToTail(e *Element[T]) error {
+	return l.move(e, false)
+}
+
+// InsertBefore adds data to the list immediately in front of the item
+// referenced by e and returns a handle to it.  Returns an InvalidElementError
+// if e is not in the list.  On a bounded list that is full, the Drop policy
+// discards data and returns a nil Element and every other policy returns a
+// CapacityExceededError.
+//
+// Runtime: O(1), retrying if another goroutine changes the neighbours first
+func (l *LockCoupled[T]) InsertBefore(e *Element[T], data T) (*Element[T], error) {
+	return l.insert(e, data, true)
+}
+
+// InsertAfter adds data to the list immediately behind the item referenced by
+// e and returns a handle to it.  Returns an InvalidElementError if e is not in
+// the list.  Full bounded lists are handled like InsertBefore.
+//
+// Runtime: O(1), retrying if another goroutine changes the neighbours first
+func (l *LockCoupled[T]) InsertAfter(e *Element[T], data T) (*Element[T], error) {
+	return l.insert(e, data, false)
+}
+
+// move takes the item referenced by e out of the list and relinks it at the
+// front or back
+func (l *LockCoupled[T]) move(e *Element[T], toHead bool) error {
+	l.gate.Lock()
+	defer l.gate.Unlock()
+	pred, succ, err := l.lockElement(e)
+	if err != nil {
+		return err
+	}
+	l.unlink(e.coupled, pred, succ)
+	succ.mu.Unlock()
+	e.coupled.mu.Unlock()
+	pred.mu.Unlock()
+	if toHead {
+		l.pushHead(e.coupled)
+	} else {
+		l.pushTail(e.coupled)
+	}
+	return nil
+}
+
+// insert adds data in front of or behind the item referenced by e
+func (l *LockCoupled[T]) insert(e *Element[T], data T, before bool) (*Element[T], error) {
+	l.gate.Lock()
+	defer l.gate.Unlock()
+	pred, succ, err := l.lockElement(e)
+	if err != nil {
+		return nil, err
+	}
+	defer pred.mu.Unlock()
+	defer e.coupled.mu.Unlock()
+	defer succ.mu.Unlock()
+	if ok, err := l.admit(context.Background(), false, nil); !ok {
+		return nil, err
+	}
+	node := &coupledNode[T]{data: data}
+	if before {
+		l.link(node, pred, e.coupled)
+	} else {
+		l.link(node, e.coupled, succ)
+	}
+	l.notEmpty.Signal()
+	return &Element[T]{coupled: node}, nil
+}
+
+// lockElement locks the node referenced by e and the nodes on either side of
+// it, in list order, and returns the neighbours.  Returns an
+// InvalidElementError, with no locks held, if e is not in the list.
+func (l *LockCoupled[T]) lockElement(e *Element[T]) (pred, succ *coupledNode[T], err error) {
+	for {
+		if err = l.checkElement(e); err != nil {
+			return
+		}
+		node := e.coupled
+		pred = node.prev.Load()
+		if pred == nil {
+			// node is being moved by MoveToHead or MoveToTail, so wait
+			// for it to be relinked
+			runtime.Gosched()
+			continue
+		}
+		pred.mu.Lock()
+		// pred may have been moved behind node since it was read, so
+		// don't wait for node's lock out of order
+		if !node.mu.TryLock() {
+			pred.mu.Unlock()
+			runtime.Gosched()
+			continue
+		}
+		// pred was read without a lock, so check that it is still in
+		// front of node, which also means node is still in the list
+		if pred.next.Load() == node && node.prev.Load() == pred {
+			succ = node.next.Load()
+			succ.mu.Lock()
+			return pred, succ, nil
+		}
+		node.mu.Unlock()
+		pred.mu.Unlock()
+	}
+}
+
+// checkElement returns an InvalidElementError if e does not reference an item
+// in the list
+func (l *LockCoupled[T]) checkElement(e *Element[T]) error {
+	if e == nil || e.node == nil && e.coupled == nil {
+		return InvalidElementError("element is nil")
+	}
+	if e.coupled == nil {
+		return InvalidElementError("element belongs to a different list")
+	}
+	switch e.coupled.owner.Load() {
+	case l:
+		return nil
+	case nil:
+		return InvalidElementError("element has been removed from its list")
+	default:
+		return InvalidElementError("element belongs to a different list")
+	}
+}
@@ -0,0 +1,156 @@
+package lists
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ElementTestSuite struct {
+	suite.Suite
+	list     *Doubly[string]
+	elements []*Element[string]
+}
+
+func TestElementTestSuite(t *testing.T) {
+	suite.Run(t, new(ElementTestSuite))
+}
+
+func (suite *ElementTestSuite) SetupTest() {
+	suite.list = NewDoublyOf[string]()
+	suite.elements = nil
+	for _, item := range []string{"b", "c", "d"} {
+		e, err := suite.list.PushTailElement(item)
+		assert.NoError(suite.T(), err)
+		suite.elements = append(suite.elements, e)
+	}
+	e, err := suite.list.PushHeadElement("a")
+	assert.NoError(suite.T(), err)
+	suite.elements = append([]*Element[string]{e}, suite.elements...)
+}
+
+func (suite *ElementTestSuite) items() (items []string) {
+	for _, item := range suite.list.All() {
+		items = append(items, item)
+	}
+	return
+}
+
+func (suite *ElementTestSuite) TestPushElement() {
+	assert.Equal(suite.T(), []string{"a", "b", "c", "d"}, suite.items())
+	for i, e := range suite.elements {
+		data, err := suite.list.Value(e)
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), suite.items()[i], data, "element %d references the wrong item", i)
+	}
+}
+
+func (suite *ElementTestSuite) TestPushElementFails() {
+	bounded := NewBoundedDoubly[string](1, Reject)
+	bounded.PushTail("a")
+	e, err := bounded.PushTailElement("b")
+	assert.Nil(suite.T(), e)
+	assert.IsType(suite.T(), CapacityExceededError(""), err)
+
+	dropping := NewBoundedDoubly[string](1, Drop)
+	dropping.PushTail("a")
+	e, err = dropping.PushHeadElement("b")
+	assert.Nil(suite.T(), e, "a dropped item has no element")
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *ElementTestSuite) TestPushElementClosed() {
+	assert.NoError(suite.T(), suite.list.Close())
+	e, err := suite.list.PushHeadElement("z")
+	assert.Nil(suite.T(), e)
+	assert.IsType(suite.T(), ClosedListError(""), err)
+	e, err = suite.list.PushTailElement("z")
+	assert.Nil(suite.T(), e)
+	assert.IsType(suite.T(), ClosedListError(""), err)
+}
+
+func (suite *ElementTestSuite) TestRemove() {
+	data, err := suite.list.Remove(suite.elements[1])
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "b", data)
+	assert.Equal(suite.T(), []string{"a", "c", "d"}, suite.items())
+	data, _ = suite.list.Remove(suite.elements[0])
+	assert.Equal(suite.T(), "a", data)
+	data, _ = suite.list.Remove(suite.elements[3])
+	assert.Equal(suite.T(), "d", data)
+	assert.Equal(suite.T(), []string{"c"}, suite.items())
+	assert.Equal(suite.T(), 1, suite.list.Size())
+	suite.list.Remove(suite.elements[2])
+	assert.True(suite.T(), suite.list.IsEmpty())
+	suite.list.PushTail("e")
+	assert.Equal(suite.T(), []string{"e"}, suite.items())
+}
+
+func (suite *ElementTestSuite) TestMove() {
+	assert.NoError(suite.T(), suite.list.MoveToHead(suite.elements[2]))
+	assert.Equal(suite.T(), []string{"c", "a", "b", "d"}, suite.items())
+	assert.NoError(suite.T(), suite.list.MoveToTail(suite.elements[0]))
+	assert.Equal(suite.T(), []string{"c", "b", "d", "a"}, suite.items())
+	assert.NoError(suite.T(), suite.list.MoveToHead(suite.elements[2]), "moving the head to the head")
+	assert.NoError(suite.T(), suite.list.MoveToTail(suite.elements[0]), "moving the tail to the tail")
+	assert.Equal(suite.T(), []string{"c", "b", "d", "a"}, suite.items())
+	assert.Equal(suite.T(), 4, suite.list.Size())
+	tail, _ := suite.list.PopTail()
+	assert.Equal(suite.T(), "a", tail)
+}
+
+func (suite *ElementTestSuite) TestInsert() {
+	e, err := suite.list.InsertBefore(suite.elements[0], "before a")
+	assert.NoError(suite.T(), err)
+	_, err = suite.list.InsertAfter(suite.elements[3], "after d")
+	assert.NoError(suite.T(), err)
+	_, err = suite.list.InsertAfter(e, "after before a")
+	assert.NoError(suite.T(), err)
+	_, err = suite.list.InsertBefore(suite.elements[2], "before c")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"before a", "after before a", "a", "b", "before c", "c", "d", "after d"}, suite.items())
+	assert.Equal(suite.T(), 8, suite.list.Size())
+	tail, _ := suite.list.PopTail()
+	assert.Equal(suite.T(), "after d", tail)
+	head, _ := suite.list.PopHead()
+	assert.Equal(suite.T(), "before a", head)
+}
+
+func (suite *ElementTestSuite) TestRemovedElement() {
+	wanted := InvalidElementError("element has been removed from its list")
+	suite.list.Remove(suite.elements[1])
+	suite.list.PopHead()
+	suite.list.Delete(0, func(data string) bool { return data == "c" })
+	for _, e := range suite.elements[:3] {
+		_, err := suite.list.Remove(e)
+		assert.Exactly(suite.T(), wanted, err, "Remove")
+		_, err = suite.list.Value(e)
+		assert.Exactly(suite.T(), wanted, err, "Value")
+		assert.Exactly(suite.T(), wanted, suite.list.MoveToHead(e), "MoveToHead")
+		assert.Exactly(suite.T(), wanted, suite.list.MoveToTail(e), "MoveToTail")
+		_, err = suite.list.InsertBefore(e, "x")
+		assert.Exactly(suite.T(), wanted, err, "InsertBefore")
+		_, err = suite.list.InsertAfter(e, "x")
+		assert.Exactly(suite.T(), wanted, err, "InsertAfter")
+	}
+	assert.Equal(suite.T(), []string{"d"}, suite.items())
+}
+
+func (suite *ElementTestSuite) TestForeignElement() {
+	other := NewDoublyOf[string]()
+	e, _ := other.PushHeadElement("z")
+	wanted := InvalidElementError("element belongs to a different list")
+	_, err := suite.list.Remove(e)
+	assert.Exactly(suite.T(), wanted, err, "Remove")
+	assert.Exactly(suite.T(), wanted, suite.list.MoveToHead(e), "MoveToHead")
+	_, err = suite.list.InsertAfter(e, "x")
+	assert.Exactly(suite.T(), wanted, err, "InsertAfter")
+	_, err = other.Remove(suite.elements[0])
+	assert.Exactly(suite.T(), wanted, err, "Remove")
+	assert.Equal(suite.T(), 4, suite.list.Size())
+	assert.Equal(suite.T(), 1, other.Size())
+
+	_, err = suite.list.Remove(nil)
+	assert.Exactly(suite.T(), InvalidElementError("element is nil"), err)
+}
@@ -0,0 +1,226 @@
+package lists
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+)
+
+var (
+	_ json.Marshaler             = (*Singly[interface{}])(nil)
+	_ json.Unmarshaler           = (*Singly[interface{}])(nil)
+	_ gob.GobEncoder             = (*Singly[interface{}])(nil)
+	_ gob.GobDecoder             = (*Singly[interface{}])(nil)
+	_ encoding.BinaryMarshaler   = (*Singly[interface{}])(nil)
+	_ encoding.BinaryUnmarshaler = (*Singly[interface{}])(nil)
+	_ json.Marshaler             = (*Doubly[interface{}])(nil)
+	_ json.Unmarshaler           = (*Doubly[interface{}])(nil)
+	_ gob.GobEncoder             = (*Doubly[interface{}])(nil)
+	_ gob.GobDecoder             = (*Doubly[interface{}])(nil)
+	_ encoding.BinaryMarshaler   = (*Doubly[interface{}])(nil)
+	_ encoding.BinaryUnmarshaler = (*Doubly[interface{}])(nil)
+)
+
+// MarshalJSON encodes the list as a JSON array of its items from head to
+// tail.
+//
+// Runtime: O(n)
+func (s *Singly[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.items())
+}
+
+// UnmarshalJSON replaces the items in the list with those in data, a JSON
+// array whose first item becomes the head.  Each item is decoded as a T, so
+// a list of structs gets structs rather than maps.  Unmarshaling into a zero
+// Singly, such as a struct field, first sets it up as NewSinglyOf would.
+// Returns a ClosedListError if the list has been closed, or a
+// CapacityExceededError if a bounded list can't hold every item; the list is
+// unchanged if any error is returned.
+//
+// Runtime: O(n)
+func (s *Singly[T]) UnmarshalJSON(data []byte) error {
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	return s.replace(items)
+}
+
+// GobEncode encodes the items of the list from head to tail with
+// encoding/gob.  Lists of interface{} need their item types registered with
+// gob.Register.
+//
+// Runtime: O(n)
+func (s *Singly[T]) GobEncode() ([]byte, error) {
+	return gobEncode(s.items())
+}
+
+// GobDecode replaces the items in the list with those encoded by GobEncode,
+// and fails like UnmarshalJSON.
+//
+// Runtime: O(n)
+func (s *Singly[T]) GobDecode(data []byte) error {
+	items, err := gobDecode[T](data)
+	if err != nil {
+		return err
+	}
+	return s.replace(items)
+}
+
+// MarshalBinary encodes the list in the same format as GobEncode
+//
+// Runtime: O(n)
+func (s *Singly[T]) MarshalBinary() ([]byte, error) {
+	return s.GobEncode()
+}
+
+// UnmarshalBinary decodes data encoded by MarshalBinary like GobDecode
+//
+// Runtime: O(n)
+func (s *Singly[T]) UnmarshalBinary(data []byte) error {
+	return s.GobDecode(data)
+}
+
+// items returns the items of the list from head to tail, or no items for a
+// zero Singly, which is left untouched so that concurrent encodes don't race
+func (s *Singly[T]) items() []T {
+	if s.rwLock == nil {
+		return []T{}
+	}
+	return s.snapshot()
+}
+
+// init sets up a zero Singly as NewSinglyOf would
+func (s *Singly[T]) init() {
+	if s.rwLock == nil {
+		*s = *newSingly[T](bounds{}, nil)
+	}
+}
+
+// replace swaps the items in the list for items
+func (s *Singly[T]) replace(items []T) error {
+	s.init()
+	s.rwLock.Lock()
+	defer s.rwLock.Unlock()
+	if err := s.bounds.checkReplace(len(items)); err != nil {
+		return err
+	}
+	s.head, s.tail, s.size = nil, nil, 0
+	for _, data := range items {
+		s.pushTail(data)
+	}
+	s.bounds.removedMany(s.size)
+	return nil
+}
+
+// MarshalJSON encodes the list as a JSON array of its items from head to
+// tail.
+//
+// Runtime: O(n)
+func (d *Doubly[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.items())
+}
+
+// UnmarshalJSON replaces the items in the list with those in data, a JSON
+// array whose first item becomes the head.  Each item is decoded as a T, so
+// a list of structs gets structs rather than maps.  Elements of the items
+// that were replaced stop being valid.  Unmarshaling into a zero Doubly, such
+// as a struct field, first sets it up as NewDoublyOf would.  Returns a
+// ClosedListError if the list has been closed, or a CapacityExceededError if
+// a bounded list can't hold every item; the list is unchanged if any error is
+// returned.
+//
+// Runtime: O(n)
+func (d *Doubly[T]) UnmarshalJSON(data []byte) error {
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	return d.replace(items)
+}
+
+// GobEncode encodes the items of the list from head to tail with
+// encoding/gob.  Lists of interface{} need their item types registered with
+// gob.Register.
+//
+// Runtime: O(n)
+func (d *Doubly[T]) GobEncode() ([]byte, error) {
+	return gobEncode(d.items())
+}
+
+// GobDecode replaces the items in the list with those encoded by GobEncode,
+// and fails like UnmarshalJSON.
+//
+// Runtime: O(n)
+func (d *Doubly[T]) GobDecode(data []byte) error {
+	items, err := gobDecode[T](data)
+	if err != nil {
+		return err
+	}
+	return d.replace(items)
+}
+
+// MarshalBinary encodes the list in the same format as GobEncode
+//
+// Runtime: O(n)
+func (d *Doubly[T]) MarshalBinary() ([]byte, error) {
+	return d.GobEncode()
+}
+
+// UnmarshalBinary decodes data encoded by MarshalBinary like GobDecode
+//
+// Runtime: O(n)
+func (d *Doubly[T]) UnmarshalBinary(data []byte) error {
+	return d.GobDecode(data)
+}
+
+// items returns the items of the list from head to tail, or no items for a
+// zero Doubly, which is left untouched so that concurrent encodes don't race
+func (d *Doubly[T]) items() []T {
+	if d.rwLock == nil {
+		return []T{}
+	}
+	return d.snapshot()
+}
+
+// init sets up a zero Doubly as NewDoublyOf would
+func (d *Doubly[T]) init() {
+	if d.rwLock == nil {
+		*d = *newDoubly[T](bounds{}, nil)
+		d.owner = &owner[T]{list: d}
+	}
+}
+
+// replace swaps the items in the list for items
+func (d *Doubly[T]) replace(items []T) error {
+	d.init()
+	d.rwLock.Lock()
+	defer d.rwLock.Unlock()
+	if err := d.bounds.checkReplace(len(items)); err != nil {
+		return err
+	}
+	// forward the old owner to one with no list, so that every old node
+	// counts as removed without visiting each of them
+	d.owner.next.Store(&owner[T]{})
+	d.owner = &owner[T]{list: d}
+	d.head, d.tail, d.size = nil, nil, 0
+	for _, data := range items {
+		d.pushTail(data)
+	}
+	d.bounds.removedMany(d.size)
+	return nil
+}
+
+func gobEncode[T any](items []T) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(items); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func gobDecode[T any](data []byte) (items []T, err error) {
+	err = gob.NewDecoder(bytes.NewReader(data)).Decode(&items)
+	return
+}
@@ -0,0 +1,144 @@
+package lists
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type encodedJob struct {
+	ID   int
+	Name string
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	s := singlyOf(3, 1, 2)
+	data, err := json.Marshal(s)
+	assert.NoError(t, err)
+	assert.Equal(t, "[3,1,2]", string(data))
+
+	decoded := NewDoublyOf[int]()
+	assert.NoError(t, json.Unmarshal(data, decoded))
+	assert.Equal(t, []int{3, 1, 2}, slices.Collect(decoded.Values()))
+	assert.Equal(t, []int{2, 1, 3}, backward(decoded))
+
+	data, err = json.Marshal(NewSinglyOf[int]())
+	assert.NoError(t, err)
+	assert.Equal(t, "[]", string(data))
+}
+
+func TestEncodeZeroList(t *testing.T) {
+	// encoding only reads the list, so a zero list can be encoded from
+	// several goroutines at once and is left as it was
+	var s Singly[int]
+	var d Doubly[int]
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data, err := json.Marshal(&s)
+			assert.NoError(t, err)
+			assert.Equal(t, "[]", string(data))
+			data, err = json.Marshal(&d)
+			assert.NoError(t, err)
+			assert.Equal(t, "[]", string(data))
+			_, err = s.GobEncode()
+			assert.NoError(t, err)
+			_, err = d.MarshalBinary()
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Nil(t, s.rwLock)
+	assert.Nil(t, d.rwLock)
+
+	data, err := d.GobEncode()
+	assert.NoError(t, err)
+	decoded := NewSinglyOf[int]()
+	assert.NoError(t, decoded.GobDecode(data))
+	assert.True(t, decoded.IsEmpty())
+}
+
+func TestJSONTypedDecode(t *testing.T) {
+	type payload struct {
+		Pending Doubly[encodedJob]
+		Done    *Singly[encodedJob]
+	}
+	data := []byte(`{"Pending":[{"ID":1,"Name":"a"},{"ID":2,"Name":"b"}],"Done":[{"ID":0,"Name":"z"}]}`)
+
+	// zero lists and nil pointers are set up while decoding
+	var out payload
+	assert.NoError(t, json.Unmarshal(data, &out))
+	assert.Equal(t, []encodedJob{{1, "a"}, {2, "b"}}, slices.Collect(out.Pending.Values()))
+	assert.Equal(t, []encodedJob{{0, "z"}}, slices.Collect(out.Done.Values()))
+	encoded, err := json.Marshal(&out)
+	assert.NoError(t, err)
+	assert.Equal(t, string(data), string(encoded))
+
+	// the decoded lists are fully usable
+	out.Pending.PushHead(encodedJob{3, "c"})
+	job, err := out.Pending.PopTail()
+	assert.NoError(t, err)
+	assert.Equal(t, encodedJob{2, "b"}, job)
+	e, _ := out.Pending.PushTailElement(encodedJob{4, "d"})
+	assert.NoError(t, out.Pending.MoveToHead(e))
+}
+
+func TestUnmarshalReplacesItems(t *testing.T) {
+	d := doublyOf(1, 2)
+	e, _ := d.PushTailElement(3)
+	assert.NoError(t, json.Unmarshal([]byte("[7,8]"), d))
+	assert.Equal(t, []int{7, 8}, slices.Collect(d.Values()))
+	assert.Equal(t, 2, d.Size())
+	_, err := d.Remove(e)
+	assert.Equal(t, InvalidElementError("element has been removed from its list"), err)
+
+	assert.Error(t, json.Unmarshal([]byte(`["x"]`), d))
+	assert.Equal(t, []int{7, 8}, slices.Collect(d.Values()), "a failed decode should leave the list unchanged")
+
+	bounded := NewBoundedSingly[int](2, Evict)
+	assert.IsType(t, CapacityExceededError(""), json.Unmarshal([]byte("[1,2,3]"), bounded))
+	assert.True(t, bounded.IsEmpty())
+	bounded.Close()
+	assert.IsType(t, ClosedListError(""), json.Unmarshal([]byte("[1]"), bounded))
+}
+
+func TestGobRoundTrip(t *testing.T) {
+	in := NewSinglyOf[encodedJob]()
+	in.PushTail(encodedJob{1, "a"})
+	in.PushTail(encodedJob{2, "b"})
+	var buf bytes.Buffer
+	assert.NoError(t, gob.NewEncoder(&buf).Encode(in))
+
+	var out Singly[encodedJob]
+	assert.NoError(t, gob.NewDecoder(&buf).Decode(&out))
+	assert.Equal(t, []encodedJob{{1, "a"}, {2, "b"}}, slices.Collect(out.Values()))
+
+	empty := NewDoublyOf[int]()
+	data, err := empty.GobEncode()
+	assert.NoError(t, err)
+	d := doublyOf(1)
+	assert.NoError(t, d.GobDecode(data))
+	assert.True(t, d.IsEmpty())
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	in := doublyOf(5, 6, 7)
+	data, err := in.MarshalBinary()
+	assert.NoError(t, err)
+	var out Doubly[int]
+	assert.NoError(t, out.UnmarshalBinary(data))
+	assert.Equal(t, []int{5, 6, 7}, slices.Collect(out.Values()))
+	assert.Equal(t, []int{7, 6, 5}, backward(&out))
+
+	s := NewSinglyOf[int]()
+	assert.NoError(t, s.UnmarshalBinary(data))
+	assert.Equal(t, []int{5, 6, 7}, slices.Collect(s.Values()))
+	assert.Error(t, s.UnmarshalBinary([]byte("garbage")))
+}
@@ -7,5 +7,63 @@ import "fmt"
 type EmptyListError string
 
 func (e EmptyListError) Error() string {
-	return fmt.Sprintf("lists: %s", e)
+	return fmt.Sprintf("lists: %s", string(e))
+}
+
+// InvalidElementError indicates that an Element can't be used with a list,
+// either because it belongs to a different list or because its item has
+// already been removed
+type InvalidElementError string
+
+func (e InvalidElementError) Error() string {
+	return fmt.Sprintf("lists: %s", string(e))
+}
+
+// IndexOutOfRangeError indicates that the requested index is not a position
+// in the list
+type IndexOutOfRangeError string
+
+func (e IndexOutOfRangeError) Error() string {
+	return fmt.Sprintf("lists: %s", string(e))
+}
+
+// CapacityExceededError indicates that an item couldn't be added because the
+// list is at capacity
+type CapacityExceededError string
+
+func (e CapacityExceededError) Error() string {
+	return fmt.Sprintf("lists: %s", string(e))
+}
+
+// ClosedListError indicates that an item couldn't be added because the list
+// has been closed
+type ClosedListError string
+
+func (e ClosedListError) Error() string {
+	return fmt.Sprintf("lists: %s", string(e))
+}
+
+// DrainedListError indicates that an item couldn't be removed because the
+// list has been closed and every item in it has already been removed.  Unlike
+// an EmptyListError, no more items will ever be available.
+type DrainedListError string
+
+func (e DrainedListError) Error() string {
+	return fmt.Sprintf("lists: %s", string(e))
+}
+
+// InvalidListError indicates that a list can't be used as the argument of an
+// operation on a list, like concatenating a list onto itself.
+type InvalidListError string
+
+func (e InvalidListError) Error() string {
+	return fmt.Sprintf("lists: %s", string(e))
+}
+
+// NoMatchError indicates that a conditional operation like PopHeadIf left the
+// list unchanged because the item at that end didn't satisfy the condition.
+type NoMatchError string
+
+func (e NoMatchError) Error() string {
+	return fmt.Sprintf("lists: %s", string(e))
 }
@@ -0,0 +1,261 @@
+/*
+Package fn provides higher-order functions over the lists in package lists:
+Map, Filter, Fold, Reduce, Partition, Any, All, Find, FindLast, FindAll,
+IndexOf and ForEach.
+
+Every function reads its list through Enumerate, which copies the items
+under the list's read lock and releases the lock before any callback runs.
+Callbacks therefore see the list as it was when the function was called, and
+may use the list, including modifying it, without deadlocking.  Nothing a
+callback changes is seen by the function that called it.  The copy costs
+O(n) memory and is made in full even by functions like Find and Any that may
+stop early.
+
+Functions that return values accept any Source, which *lists.Singly,
+*lists.Doubly and *lists.LockCoupled all satisfy:
+
+	total := fn.Fold(orders, 0, func(sum int, o Order) int { return sum + o.Total })
+
+Map, Filter, Partition and FindAll return a lists.List of the same kind as
+the source: a *lists.Singly when the source is one, and a *lists.Doubly
+otherwise.  Map, Filter and Partition also come with Singly and Doubly
+variants, which return the concrete type and so let the result be a
+different kind of list from the source.  The lists they return are new,
+unbounded and guarded by their own sync.RWMutex, and the source is left
+unchanged:
+
+	names := fn.MapDoubly(users, func(u User) string { return u.Name })
+*/
+package fn
+
+import (
+	"iter"
+
+	"github.com/suicidejack/go-various/lists"
+)
+
+// Source is a list that the functions in this package can read.
+// *lists.Singly, *lists.Doubly and *lists.LockCoupled satisfy it.
+type Source[T any] interface {
+	Enumerate() iter.Seq2[int, T]
+}
+
+// ForEach calls f with the index and data of every item from the head of src
+// to the tail.
+//
+// Runtime: O(n)
+func ForEach[T any](src Source[T], f func(i int, data T)) {
+	for i, data := range src.Enumerate() {
+		f(i, data)
+	}
+}
+
+// Fold combines the items of src from head to tail, starting from initial
+// and replacing it with f(acc, data) for each item, and returns the result.
+// Returns initial if src is empty.
+//
+// Runtime: O(n)
+func Fold[T, A any](src Source[T], initial A, f func(acc A, data T) A) A {
+	acc := initial
+	for _, data := range src.Enumerate() {
+		acc = f(acc, data)
+	}
+	return acc
+}
+
+// Reduce combines the items of src like Fold, but starts from the first item
+// instead of an initial value.  Returns a lists.EmptyListError if src is
+// empty.
+//
+// Runtime: O(n)
+func Reduce[T any](src Source[T], f func(acc, data T) T) (result T, err error) {
+	first := true
+	for _, data := range src.Enumerate() {
+		if first {
+			result, first = data, false
+		} else {
+			result = f(result, data)
+		}
+	}
+	if first {
+		return result, lists.EmptyListError("can't reduce an empty list")
+	}
+	return result, nil
+}
+
+// Any returns true if pred returns true for at least one item of src.  pred
+// isn't called for the items after the first match.
+//
+// Runtime: O(n)
+func Any[T any](src Source[T], pred func(data T) bool) bool {
+	return IndexOf(src, pred) >= 0
+}
+
+// All returns true if pred returns true for every item of src, which
+// includes when src is empty.  pred isn't called for the items after the
+// first one it returns false for.
+//
+// Runtime: O(n)
+func All[T any](src Source[T], pred func(data T) bool) bool {
+	for _, data := range src.Enumerate() {
+		if !pred(data) {
+			return false
+		}
+	}
+	return true
+}
+
+// Find returns the first item of src, counting from the head, that pred
+// returns true for.  found is false if there is no such item.
+//
+// Runtime: O(n)
+func Find[T any](src Source[T], pred func(data T) bool) (data T, found bool) {
+	for _, item := range src.Enumerate() {
+		if pred(item) {
+			return item, true
+		}
+	}
+	return data, false
+}
+
+// FindLast returns the last item of src, counting from the head, that pred
+// returns true for.  found is false if there is no such item.  pred is called
+// for every item.
+//
+// Runtime: O(n)
+func FindLast[T any](src Source[T], pred func(data T) bool) (data T, found bool) {
+	for _, item := range src.Enumerate() {
+		if pred(item) {
+			data, found = item, true
+		}
+	}
+	return
+}
+
+// IndexOf returns the index of the first item of src that pred returns true
+// for, or -1 if there is no such item.
+//
+// Runtime: O(n)
+func IndexOf[T any](src Source[T], pred func(data T) bool) int {
+	for i, data := range src.Enumerate() {
+		if pred(data) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Map returns a new list of the same kind as src holding f(data) for every
+// item of src, in the same order.
+//
+// Runtime: O(n)
+func Map[T, U any](src Source[T], f func(data T) U) lists.List[U] {
+	if _, ok := src.(*lists.Singly[T]); ok {
+		return MapSingly(src, f)
+	}
+	return MapDoubly(src, f)
+}
+
+// MapSingly returns a new Singly holding f(data) for every item of src, in
+// the same order.
+//
+// Runtime: O(n)
+func MapSingly[T, U any](src Source[T], f func(data T) U) *lists.Singly[U] {
+	dst := lists.NewSinglyOf[U]()
+	for _, data := range src.Enumerate() {
+		dst.PushTail(f(data))
+	}
+	return dst
+}
+
+// MapDoubly returns a new Doubly holding f(data) for every item of src, in
+// the same order.
+//
+// Runtime: O(n)
+func MapDoubly[T, U any](src Source[T], f func(data T) U) *lists.Doubly[U] {
+	dst := lists.NewDoublyOf[U]()
+	for _, data := range src.Enumerate() {
+		dst.PushTail(f(data))
+	}
+	return dst
+}
+
+// Filter returns a new list of the same kind as src holding the items of src
+// that pred returns true for, in the same order.
+//
+// Runtime: O(n)
+func Filter[T any](src Source[T], pred func(data T) bool) lists.List[T] {
+	matched, _ := Partition(src, pred)
+	return matched
+}
+
+// FindAll is Filter under the name used alongside Find and FindLast.
+//
+// Runtime: O(n)
+func FindAll[T any](src Source[T], pred func(data T) bool) lists.List[T] {
+	return Filter(src, pred)
+}
+
+// FilterSingly returns a new Singly holding the items of src that pred
+// returns true for, in the same order.
+//
+// Runtime: O(n)
+func FilterSingly[T any](src Source[T], pred func(data T) bool) *lists.Singly[T] {
+	matched, _ := PartitionSingly(src, pred)
+	return matched
+}
+
+// FilterDoubly returns a new Doubly holding the items of src that pred
+// returns true for, in the same order.
+//
+// Runtime: O(n)
+func FilterDoubly[T any](src Source[T], pred func(data T) bool) *lists.Doubly[T] {
+	matched, _ := PartitionDoubly(src, pred)
+	return matched
+}
+
+// Partition returns two new lists of the same kind as src, one holding the
+// items of src that pred returns true for and the other holding the rest.
+// Both keep the order of src.  pred is called once for each item.
+//
+// Runtime: O(n)
+func Partition[T any](src Source[T], pred func(data T) bool) (matched, rest lists.List[T]) {
+	if _, ok := src.(*lists.Singly[T]); ok {
+		return PartitionSingly(src, pred)
+	}
+	return PartitionDoubly(src, pred)
+}
+
+// PartitionSingly returns two new Singly lists, one holding the items of src
+// that pred returns true for and the other holding the rest.  Both keep the
+// order of src.  pred is called once for each item.
+//
+// Runtime: O(n)
+func PartitionSingly[T any](src Source[T], pred func(data T) bool) (matched, rest *lists.Singly[T]) {
+	matched, rest = lists.NewSinglyOf[T](), lists.NewSinglyOf[T]()
+	for _, data := range src.Enumerate() {
+		if pred(data) {
+			matched.PushTail(data)
+		} else {
+			rest.PushTail(data)
+		}
+	}
+	return
+}
+
+// PartitionDoubly returns two new Doubly lists, one holding the items of src
+// that pred returns true for and the other holding the rest.  Both keep the
+// order of src.  pred is called once for each item.
+//
+// Runtime: O(n)
+func PartitionDoubly[T any](src Source[T], pred func(data T) bool) (matched, rest *lists.Doubly[T]) {
+	matched, rest = lists.NewDoublyOf[T](), lists.NewDoublyOf[T]()
+	for _, data := range src.Enumerate() {
+		if pred(data) {
+			matched.PushTail(data)
+		} else {
+			rest.PushTail(data)
+		}
+	}
+	return
+}
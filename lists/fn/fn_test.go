@@ -0,0 +1,135 @@
+package fn
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/suicidejack/go-various/lists"
+)
+
+func singlyOf(values ...int) *lists.Singly[int] {
+	s := lists.NewSinglyOf[int]()
+	for _, v := range values {
+		s.PushTail(v)
+	}
+	return s
+}
+
+func doublyOf(values ...int) *lists.Doubly[int] {
+	d := lists.NewDoublyOf[int]()
+	for _, v := range values {
+		d.PushTail(v)
+	}
+	return d
+}
+
+func isEven(data int) bool {
+	return data%2 == 0
+}
+
+func TestForEach(t *testing.T) {
+	var seen []int
+	ForEach(doublyOf(3, 4), func(i, data int) {
+		seen = append(seen, i, data)
+	})
+	assert.Equal(t, []int{0, 3, 1, 4}, seen)
+}
+
+func TestFoldAndReduce(t *testing.T) {
+	s := singlyOf(1, 2, 3)
+	assert.Equal(t, "123", Fold(s, "", func(acc string, data int) string {
+		return acc + strconv.Itoa(data)
+	}))
+	assert.Equal(t, 10, Fold(singlyOf(), 10, func(acc, data int) int { return acc + data }))
+
+	sum, err := Reduce(s, func(acc, data int) int { return acc + data })
+	assert.NoError(t, err)
+	assert.Equal(t, 6, sum)
+	one, err := Reduce(doublyOf(1), func(acc, data int) int { return acc * 100 })
+	assert.NoError(t, err)
+	assert.Equal(t, 1, one)
+	_, err = Reduce(doublyOf(), func(acc, data int) int { return acc + data })
+	assert.IsType(t, lists.EmptyListError(""), err)
+}
+
+func TestAnyAndAll(t *testing.T) {
+	assert.True(t, Any(singlyOf(1, 2), isEven))
+	assert.False(t, Any(singlyOf(1, 3), isEven))
+	assert.False(t, Any(singlyOf(), isEven))
+	assert.True(t, All(doublyOf(2, 4), isEven))
+	assert.False(t, All(doublyOf(2, 3), isEven))
+	assert.True(t, All(doublyOf(), isEven))
+}
+
+func TestFind(t *testing.T) {
+	d := doublyOf(1, 2, 3, 4, 5)
+	data, found := Find(d, isEven)
+	assert.True(t, found)
+	assert.Equal(t, 2, data)
+	data, found = FindLast(d, isEven)
+	assert.True(t, found)
+	assert.Equal(t, 4, data)
+	_, found = Find(d, func(data int) bool { return data > 5 })
+	assert.False(t, found)
+	_, found = FindLast(singlyOf(), isEven)
+	assert.False(t, found)
+
+	assert.Equal(t, 1, IndexOf(d, isEven))
+	assert.Equal(t, -1, IndexOf(d, func(data int) bool { return data > 5 }))
+	all := FindAll(d, isEven)
+	assert.IsType(t, &lists.Doubly[int]{}, all)
+	assert.Equal(t, []int{2, 4}, slices.Collect(all.(*lists.Doubly[int]).Values()))
+}
+
+func TestMap(t *testing.T) {
+	s := singlyOf(1, 2)
+	d := MapDoubly(s, strconv.Itoa)
+	assert.Equal(t, []string{"1", "2"}, slices.Collect(d.Values()))
+	back := MapSingly(d, func(data string) int {
+		n, _ := strconv.Atoi(data)
+		return n * 10
+	})
+	assert.Equal(t, []int{10, 20}, slices.Collect(back.Values()))
+	assert.Equal(t, []int{1, 2}, slices.Collect(s.Values()), "the source should be unchanged")
+
+	same := Map(s, strconv.Itoa)
+	assert.IsType(t, &lists.Singly[string]{}, same)
+	assert.Equal(t, []string{"1", "2"}, slices.Collect(same.(*lists.Singly[string]).Values()))
+	assert.IsType(t, &lists.Doubly[int]{}, Map(d, func(data string) int { return len(data) }))
+}
+
+func TestFilterAndPartition(t *testing.T) {
+	s := singlyOf(1, 2, 3, 4)
+	assert.Equal(t, []int{2, 4}, slices.Collect(FilterSingly(s, isEven).Values()))
+	assert.Equal(t, []int{2, 4}, slices.Collect(FilterDoubly(s, isEven).Values()))
+
+	even, odd := PartitionDoubly(s, isEven)
+	assert.Equal(t, []int{2, 4}, slices.Collect(even.Values()))
+	assert.Equal(t, []int{1, 3}, slices.Collect(odd.Values()))
+	evenS, oddS := PartitionSingly(doublyOf(1, 2, 3, 4), isEven)
+	assert.Equal(t, 2, evenS.Size())
+	assert.Equal(t, 2, oddS.Size())
+	assert.Equal(t, 4, s.Size(), "the source should be unchanged")
+
+	filtered := Filter(s, isEven)
+	assert.IsType(t, &lists.Singly[int]{}, filtered)
+	assert.Equal(t, []int{2, 4}, slices.Collect(filtered.(*lists.Singly[int]).Values()))
+	matched, rest := Partition(doublyOf(1, 2, 3), isEven)
+	assert.IsType(t, &lists.Doubly[int]{}, matched)
+	assert.Equal(t, 1, matched.Size())
+	assert.Equal(t, 2, rest.Size())
+}
+
+func TestCallbackMayModifySource(t *testing.T) {
+	// callbacks run without the lock, so they can modify the list they are
+	// reading without deadlocking, and the function sees the original items
+	d := doublyOf(1, 2, 3)
+	doubled := MapSingly(d, func(data int) int {
+		d.PushTail(data)
+		return data * 2
+	})
+	assert.Equal(t, []int{2, 4, 6}, slices.Collect(doubled.Values()))
+	assert.Equal(t, 6, d.Size())
+}
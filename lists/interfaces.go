@@ -0,0 +1,56 @@
+package lists
+
+// Stack is a LIFO (last-in first-out) collection.  Items are added and
+// removed at the head.  PushHead returns an error if the item couldn't be
+// added; Singly and Doubly always add it, but other implementations may not.
+type Stack[T any] interface {
+	Size() int
+	IsEmpty() bool
+	PushHead(data T) error
+	PopHead() (data T, err error)
+}
+
+// Queue is a FIFO (first-in first-out) collection.  Items are added at the
+// tail and removed from the head.  PushTail returns an error if the item
+// couldn't be added, as for Stack.
+type Queue[T any] interface {
+	Size() int
+	IsEmpty() bool
+	PushTail(data T) error
+	PopHead() (data T, err error)
+}
+
+// Deque is a double-ended queue.  Items can be added and removed at either
+// end.
+type Deque[T any] interface {
+	Stack[T]
+	Queue[T]
+	PopTail() (data T, err error)
+}
+
+// List is the full method set shared by Singly and Doubly
+type List[T any] interface {
+	Deque[T]
+	Contains(comparison func(data T) (exists bool)) bool
+	Delete(numItems int, comparison func(data T) (shouldDelete bool)) (numDeleted int)
+}
+
+var (
+	_ List[interface{}] = (*Singly[interface{}])(nil)
+	_ List[interface{}] = (*Doubly[interface{}])(nil)
+)
+
+// NewStack creates a new empty LIFO stack backed by a Singly
+func NewStack[T any]() Stack[T] {
+	return NewSinglyOf[T]()
+}
+
+// NewQueue creates a new empty FIFO queue backed by a Doubly
+func NewQueue[T any]() Queue[T] {
+	return NewDoublyOf[T]()
+}
+
+// NewDeque creates a new empty double-ended queue backed by a Doubly
+func NewDeque[T any]() Deque[T] {
+	return NewDoublyOf[T]()
+}
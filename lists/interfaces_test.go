@@ -0,0 +1,49 @@
+package lists
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStack(t *testing.T) {
+	stack := NewStack[int]()
+	assert.IsType(t, &Singly[int]{}, stack)
+	for i := 0; i < 3; i++ {
+		stack.PushHead(i)
+	}
+	for i := 2; i >= 0; i-- {
+		item, err := stack.PopHead()
+		assert.NoError(t, err)
+		assert.Equal(t, i, item)
+	}
+	assert.True(t, stack.IsEmpty())
+}
+
+func TestNewQueue(t *testing.T) {
+	queue := NewQueue[int]()
+	assert.IsType(t, &Doubly[int]{}, queue)
+	for i := 0; i < 3; i++ {
+		queue.PushTail(i)
+	}
+	for i := 0; i < 3; i++ {
+		item, err := queue.PopHead()
+		assert.NoError(t, err)
+		assert.Equal(t, i, item)
+	}
+	_, err := queue.PopHead()
+	assert.IsType(t, EmptyListError(""), err)
+}
+
+func TestNewDeque(t *testing.T) {
+	deque := NewDeque[string]()
+	assert.IsType(t, &Doubly[string]{}, deque)
+	deque.PushHead("b")
+	deque.PushHead("a")
+	deque.PushTail("c")
+	assert.Equal(t, 3, deque.Size())
+	item, _ := deque.PopTail()
+	assert.Equal(t, "c", item)
+	item, _ = deque.PopHead()
+	assert.Equal(t, "a", item)
+}
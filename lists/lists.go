@@ -1,14 +1,73 @@
 /*
-Package lists provides an implementation of goroutine (thread) safe lists.
-It uses a RWMutex (http://golang.org/pkg/sync/#RWMutex) to manage access
-to the lists.
-
-Note that you probably don't need to use this package if you are looking for
-a queue (FIFO, first-in first-out) data structure.  You can use a channel instead.
-This package could be useful for LIFO, last-in last-out, (singly-linked list)
-or LIFO and FIFO functionality (doubly-linked list) when it is required.  I would
-recommend reading http://blog.golang.org/share-memory-by-communicating before using
-this package if you have not.
+Package lists provides goroutine (thread) safe lists.
+
+Singly and Doubly, the singly- and doubly-linked lists, guard each list with a
+sync.RWMutex (http://golang.org/pkg/sync/#RWMutex) by default.  WithLocker
+replaces it with a lock of your own, for example to share one lock between
+several lists, and WithoutLocking removes locking altogether for a list that
+only one goroutine uses at a time:
+
+	jobs := lists.NewDoublyOf[Job](lists.WithLocker(&mu))
+
+For lists that many goroutines use at once, other types lock less of the
+list: LockCoupled is a doubly-linked list with a lock on every node,
+TwoLockQueue a FIFO queue with one lock for each end, and LockFreeQueue,
+LockFreeStack and SortedSet take no locks at all.  Package durable has a deque
+and a queue kept on disk.
+
+Singly and Doubly are type-parameterized, so a Singly[string] or
+Doubly[*Job] hands back typed data from PopHead and PopTail and passes typed
+data to Contains and Delete callbacks.  NewSingly and NewDoubly still create lists of interface{}
+for callers that predate type parameters: an UntypedSingly or UntypedDoubly,
+whose PushHead and PushTail keep their original signatures even though those
+of Singly[T] and Doubly[T] return an error.  Code that names the list types,
+such as a field of type *lists.Singly, must now give the type argument, as in
+*lists.Singly[interface{}].
+
+The List, Deque, Queue and Stack interfaces describe the method sets shared by
+the lists so that fields can depend on a role rather than an implementation.
+NewStack, NewQueue and NewDeque return the narrowest interface for that role.
+
+The lists can be read without removing items by ranging over All, Values,
+Enumerate or, for a Doubly or LockCoupled, Backward:
+
+	for i, job := range queue.All() {
+		fmt.Println(i, job)
+	}
+
+In a Singly or Doubly, All, Values and Backward hold the read lock for the
+whole loop while Enumerate ranges over a snapshot; see each method for
+details.
+
+A list can also stand in for a channel between producers and consumers.
+PopHeadWait and PopTailWait wait for items, NewBoundedSingly,
+NewBoundedDoubly and NewBoundedLockCoupled cap the number of items with an
+OverflowPolicy, and Close and Done behave like closing and draining a channel
+while still allowing LIFO access.
+
+Each method takes the lock on its own, so a sequence of calls can be
+interleaved with other goroutines.  Do runs a callback under the write lock
+instead, and rolls back everything it changed if it returns an error:
+
+	err := queue.Do(func(tx lists.ListTx[Job]) error {
+		if tx.Size() < 2 {
+			return errNotEnough
+		}
+		a, _ := tx.PopHead()
+		b, _ := tx.PopHead()
+		return run(a, b)
+	})
+
+Move and MoveWait take an item from one end of a list and add it to an end of
+another in one step, so that it is never in neither list or in both:
+
+	job, err := lists.Move(pending, lists.Head, inFlight, lists.Tail)
+
+If all you need is to hand items from producers to consumers, a channel may
+still be enough, and I would recommend reading
+http://blog.golang.org/share-memory-by-communicating before using this package
+if you have not.  The lists are for when you also need to look at, search,
+reorder or remove items that are still waiting, or to use both ends.
 
 Submit any issues or feature requests here: https://github.com/suicidejack/go-various/issues
 */
@@ -0,0 +1,311 @@
+/*
+Package liststest provides a conformance test suite for implementations of
+the lists.List interface.
+
+The suite only uses the public List API, so it can check any list type,
+including wrappers around Singly and Doubly, against the same specification:
+
+	func TestMyList(t *testing.T) {
+		liststest.RunList(t, func() lists.List[int] { return NewMyList[int]() })
+	}
+*/
+package liststest
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/suicidejack/go-various/lists"
+)
+
+// ListSuite checks the behavioral contract of a lists.List.  NewList must
+// return a new, empty list every time it is called.  Set SingleGoroutine to
+// skip the concurrency tests for lists that aren't goroutine-safe.
+type ListSuite struct {
+	suite.Suite
+	NewList         func() lists.List[int]
+	SingleGoroutine bool
+}
+
+// RunList runs the ListSuite against lists created by newList
+func RunList(t *testing.T, newList func() lists.List[int]) {
+	suite.Run(t, &ListSuite{NewList: newList})
+}
+
+// RunSingleGoroutineList runs the ListSuite, without its concurrency tests,
+// against lists created by newList
+func RunSingleGoroutineList(t *testing.T, newList func() lists.List[int]) {
+	suite.Run(t, &ListSuite{NewList: newList, SingleGoroutine: true})
+}
+
+// drain pops every item from the head of list and returns them in order
+func drain(list lists.List[int]) (items []int) {
+	for {
+		item, err := list.PopHead()
+		if err != nil {
+			return
+		}
+		items = append(items, item)
+	}
+}
+
+func equalTo(item int) func(int) bool {
+	return func(data int) bool { return data == item }
+}
+
+func (suite *ListSuite) TestEmpty() {
+	list := suite.NewList()
+	assert.Equal(suite.T(), 0, list.Size(), "new list should have no items")
+	assert.True(suite.T(), list.IsEmpty(), "new list should be empty")
+	_, err := list.PopHead()
+	assert.IsType(suite.T(), lists.EmptyListError(""), err, "PopHead: wanted EmptyListError")
+	_, err = list.PopTail()
+	assert.IsType(suite.T(), lists.EmptyListError(""), err, "PopTail: wanted EmptyListError")
+	assert.False(suite.T(), list.Contains(func(int) bool { return true }), "empty list should not contain anything")
+	assert.Equal(suite.T(), 0, list.Delete(0, func(int) bool { return true }), "expected 0 items to be deleted from an empty list")
+	assert.Equal(suite.T(), 0, list.Size(), "failed operations should not change the size")
+}
+
+func (suite *ListSuite) TestPushHeadPopHead() {
+	list := suite.NewList()
+	for i := 0; i < 10; i++ {
+		assert.NoError(suite.T(), list.PushHead(i), "PushHead should succeed on a new list")
+	}
+	assert.Equal(suite.T(), []int{9, 8, 7, 6, 5, 4, 3, 2, 1, 0}, drain(list), "PushHead/PopHead should be LIFO")
+}
+
+func (suite *ListSuite) TestPushTailPopHead() {
+	list := suite.NewList()
+	for i := 0; i < 10; i++ {
+		assert.NoError(suite.T(), list.PushTail(i), "PushTail should succeed on a new list")
+	}
+	assert.Equal(suite.T(), []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, drain(list), "PushTail/PopHead should be FIFO")
+}
+
+func (suite *ListSuite) TestPopTail() {
+	list := suite.NewList()
+	for i := 0; i < 10; i++ {
+		list.PushHead(i)
+	}
+	for i := 0; i < 10; i++ {
+		item, err := list.PopTail()
+		assert.NoError(suite.T(), err, "list should contain items")
+		assert.Equal(suite.T(), i, item, "PopTail item %d is incorrect", i)
+	}
+	_, err := list.PopTail()
+	assert.IsType(suite.T(), lists.EmptyListError(""), err, "wanted EmptyListError")
+}
+
+func (suite *ListSuite) TestMixedEnds() {
+	list := suite.NewList()
+	list.PushHead(2)
+	list.PushTail(3)
+	list.PushHead(1)
+	list.PushTail(4)
+	item, _ := list.PopTail()
+	assert.Equal(suite.T(), 4, item)
+	list.PushTail(5)
+	item, _ = list.PopHead()
+	assert.Equal(suite.T(), 1, item)
+	list.PushHead(0)
+	assert.Equal(suite.T(), []int{0, 2, 3, 5}, drain(list))
+
+	list.PushHead(1)
+	item, _ = list.PopTail()
+	assert.Equal(suite.T(), 1, item, "single item should be both head and tail")
+	assert.True(suite.T(), list.IsEmpty())
+	list.PushTail(2)
+	item, _ = list.PopHead()
+	assert.Equal(suite.T(), 2, item, "single item should be both head and tail")
+	assert.True(suite.T(), list.IsEmpty())
+}
+
+func (suite *ListSuite) TestSize() {
+	list := suite.NewList()
+	for i := 0; i < 5; i++ {
+		list.PushHead(i)
+		assert.Equal(suite.T(), i+1, list.Size(), "PushHead: expected list to be of size %d", i+1)
+		assert.False(suite.T(), list.IsEmpty(), "PushHead")
+	}
+	for i := 5; i < 10; i++ {
+		list.PushTail(i)
+		assert.Equal(suite.T(), i+1, list.Size(), "PushTail: expected list to be of size %d", i+1)
+	}
+	for i := 9; i >= 5; i-- {
+		list.PopHead()
+		assert.Equal(suite.T(), i, list.Size(), "PopHead: expected list to be of size %d", i)
+	}
+	for i := 4; i >= 2; i-- {
+		list.PopTail()
+		assert.Equal(suite.T(), i, list.Size(), "PopTail: expected list to be of size %d", i)
+	}
+	list.Delete(0, func(int) bool { return true })
+	assert.Equal(suite.T(), 0, list.Size(), "Delete: expected list to be of size 0")
+	assert.True(suite.T(), list.IsEmpty(), "Delete")
+}
+
+func (suite *ListSuite) TestContains() {
+	list := suite.NewList()
+	for i := 0; i < 5; i++ {
+		list.PushTail(i)
+	}
+	for i := 0; i < 5; i++ {
+		assert.True(suite.T(), list.Contains(equalTo(i)), "list item %d is missing", i)
+	}
+	assert.False(suite.T(), list.Contains(equalTo(5)), "list should not contain 5")
+
+	var seen []int
+	list.Contains(func(data int) bool {
+		seen = append(seen, data)
+		return data == 2
+	})
+	assert.Equal(suite.T(), []int{0, 1, 2}, seen, "Contains should scan from head to tail and stop at the first match")
+	assert.Equal(suite.T(), 5, list.Size(), "Contains should not modify the list")
+}
+
+func (suite *ListSuite) TestDeleteAll() {
+	list := suite.NewList()
+	for i := 0; i < 10; i++ {
+		list.PushTail(i)
+	}
+	numDeleted := list.Delete(0, func(data int) bool { return data%2 == 0 })
+	assert.Equal(suite.T(), 5, numDeleted, "numItems 0 should delete every match")
+	numDeleted = list.Delete(-1, func(data int) bool { return data > 6 })
+	assert.Equal(suite.T(), 2, numDeleted, "negative numItems should delete every match")
+	numDeleted = list.Delete(0, equalTo(100))
+	assert.Equal(suite.T(), 0, numDeleted, "expected 0 items to be deleted")
+	assert.Equal(suite.T(), 3, list.Size())
+	assert.Equal(suite.T(), []int{1, 3, 5}, drain(list))
+}
+
+func (suite *ListSuite) TestDeleteLimit() {
+	list := suite.NewList()
+	for i := 0; i < 10; i++ {
+		list.PushTail(i)
+	}
+	numDeleted := list.Delete(3, func(data int) bool { return data%2 == 1 })
+	assert.Equal(suite.T(), 3, numDeleted, "Delete should stop after numItems matches")
+	assert.Equal(suite.T(), 7, list.Size())
+	numDeleted = list.Delete(5, func(data int) bool { return data > 6 })
+	assert.Equal(suite.T(), 3, numDeleted, "Delete should delete fewer than numItems when there are fewer matches")
+	assert.Equal(suite.T(), []int{0, 2, 4, 6}, drain(list), "Delete should remove the first matches from the head")
+}
+
+func (suite *ListSuite) TestDeleteEnds() {
+	list := suite.NewList()
+	for i := 0; i < 5; i++ {
+		list.PushTail(i)
+	}
+	assert.Equal(suite.T(), 1, list.Delete(0, equalTo(0)), "list should delete the head")
+	assert.Equal(suite.T(), 1, list.Delete(0, equalTo(4)), "list should delete the tail")
+	list.PushHead(10)
+	list.PushTail(11)
+	item, _ := list.PopTail()
+	assert.Equal(suite.T(), 11, item, "tail is incorrect after Delete")
+	item, _ = list.PopHead()
+	assert.Equal(suite.T(), 10, item, "head is incorrect after Delete")
+	assert.Equal(suite.T(), 3, list.Delete(0, func(int) bool { return true }))
+	assert.True(suite.T(), list.IsEmpty())
+	list.PushTail(12)
+	item, _ = list.PopHead()
+	assert.Equal(suite.T(), 12, item, "list should be reusable after deleting everything")
+}
+
+func (suite *ListSuite) TestConcurrentPushPop() {
+	if suite.SingleGoroutine {
+		suite.T().Skip("list is not goroutine-safe")
+	}
+	const goroutines, perGoroutine = 8, 250
+	list := suite.NewList()
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				if i%2 == 0 {
+					list.PushHead(g*perGoroutine + i)
+				} else {
+					list.PushTail(g*perGoroutine + i)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	assert.Equal(suite.T(), goroutines*perGoroutine, list.Size())
+
+	var mu sync.Mutex
+	var popped []int
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for {
+				var item int
+				var err error
+				if g%2 == 0 {
+					item, err = list.PopHead()
+				} else {
+					item, err = list.PopTail()
+				}
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				popped = append(popped, item)
+				mu.Unlock()
+			}
+		}(g)
+	}
+	wg.Wait()
+	sort.Ints(popped)
+	assert.Len(suite.T(), popped, goroutines*perGoroutine, "every item should be popped exactly once")
+	for i, item := range popped {
+		if !assert.Equal(suite.T(), i, item, "item %d was lost or duplicated", i) {
+			break
+		}
+	}
+	assert.True(suite.T(), list.IsEmpty())
+}
+
+func (suite *ListSuite) TestConcurrentReadersAndWriters() {
+	if suite.SingleGoroutine {
+		suite.T().Skip("list is not goroutine-safe")
+	}
+	const goroutines, perGoroutine = 4, 200
+	list := suite.NewList()
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(3)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				list.PushTail(g*perGoroutine + i)
+			}
+		}(g)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				list.Contains(equalTo(i))
+				list.Size()
+				list.IsEmpty()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				list.Delete(1, func(data int) bool { return data%7 == 0 })
+			}
+		}()
+	}
+	wg.Wait()
+	size := list.Size()
+	remaining := drain(list)
+	assert.Len(suite.T(), remaining, size, "Size should match the number of items in the list")
+	for _, item := range remaining {
+		assert.True(suite.T(), item >= 0 && item < goroutines*perGoroutine, "unexpected item %d", item)
+	}
+}
@@ -0,0 +1,106 @@
+package liststest
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/suicidejack/go-various/lists"
+)
+
+// QueueSuite checks the behavioral contract of a lists.Queue.  NewQueue must
+// return a new, empty queue every time it is called.
+type QueueSuite struct {
+	suite.Suite
+	NewQueue func() lists.Queue[int]
+}
+
+// RunQueue runs the QueueSuite against queues created by newQueue
+func RunQueue(t *testing.T, newQueue func() lists.Queue[int]) {
+	suite.Run(t, &QueueSuite{NewQueue: newQueue})
+}
+
+func (suite *QueueSuite) TestEmpty() {
+	queue := suite.NewQueue()
+	assert.Equal(suite.T(), 0, queue.Size(), "new queue should have no items")
+	assert.True(suite.T(), queue.IsEmpty(), "new queue should be empty")
+	_, err := queue.PopHead()
+	assert.IsType(suite.T(), lists.EmptyListError(""), err, "wanted EmptyListError")
+	assert.Equal(suite.T(), 0, queue.Size(), "failed operations should not change the size")
+}
+
+func (suite *QueueSuite) TestFIFO() {
+	queue := suite.NewQueue()
+	for i := 0; i < 10; i++ {
+		assert.NoError(suite.T(), queue.PushTail(i), "PushTail should succeed on a new queue")
+		assert.Equal(suite.T(), i+1, queue.Size(), "PushTail: expected queue to be of size %d", i+1)
+		assert.False(suite.T(), queue.IsEmpty())
+	}
+	for i := 0; i < 10; i++ {
+		item, err := queue.PopHead()
+		assert.NoError(suite.T(), err, "queue should contain items")
+		assert.Equal(suite.T(), i, item, "PushTail/PopHead should be FIFO")
+		assert.Equal(suite.T(), 9-i, queue.Size(), "PopHead: expected queue to be of size %d", 9-i)
+	}
+	assert.True(suite.T(), queue.IsEmpty())
+	_, err := queue.PopHead()
+	assert.IsType(suite.T(), lists.EmptyListError(""), err, "wanted EmptyListError")
+	queue.PushTail(10)
+	item, _ := queue.PopHead()
+	assert.Equal(suite.T(), 10, item, "queue should be reusable after being emptied")
+}
+
+func (suite *QueueSuite) TestConcurrentProducersConsumers() {
+	const producers, perProducer = 4, 1000
+	queue := suite.NewQueue()
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				queue.PushTail(p*perProducer + i)
+			}
+		}(p)
+	}
+	results := make(chan []int, producers)
+	for c := 0; c < producers; c++ {
+		go func() {
+			var mine []int
+			for len(mine) < perProducer/2 {
+				if item, err := queue.PopHead(); err == nil {
+					mine = append(mine, item)
+				}
+			}
+			results <- mine
+		}()
+	}
+	wg.Wait()
+	seen := make(map[int]bool)
+	last := make(map[int]int)
+	for c := 0; c < producers; c++ {
+		for k := range last {
+			delete(last, k)
+		}
+		for _, item := range <-results {
+			assert.False(suite.T(), seen[item], "item %d was popped twice", item)
+			seen[item] = true
+			producer := item / perProducer
+			if prev, ok := last[producer]; ok {
+				assert.True(suite.T(), item > prev, "items from one producer should be popped in order")
+			}
+			last[producer] = item
+		}
+	}
+	for {
+		item, err := queue.PopHead()
+		if err != nil {
+			break
+		}
+		assert.False(suite.T(), seen[item], "item %d was popped twice", item)
+		seen[item] = true
+	}
+	assert.Len(suite.T(), seen, producers*perProducer, "every item should be popped exactly once")
+	assert.Equal(suite.T(), 0, queue.Size())
+}
@@ -0,0 +1,95 @@
+package liststest
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/suicidejack/go-various/lists"
+)
+
+// StackSuite checks the behavioral contract of a lists.Stack.  NewStack must
+// return a new, empty stack every time it is called.
+type StackSuite struct {
+	suite.Suite
+	NewStack func() lists.Stack[int]
+}
+
+// RunStack runs the StackSuite against stacks created by newStack
+func RunStack(t *testing.T, newStack func() lists.Stack[int]) {
+	suite.Run(t, &StackSuite{NewStack: newStack})
+}
+
+func (suite *StackSuite) TestEmpty() {
+	stack := suite.NewStack()
+	assert.Equal(suite.T(), 0, stack.Size(), "new stack should have no items")
+	assert.True(suite.T(), stack.IsEmpty(), "new stack should be empty")
+	_, err := stack.PopHead()
+	assert.IsType(suite.T(), lists.EmptyListError(""), err, "wanted EmptyListError")
+	assert.Equal(suite.T(), 0, stack.Size(), "failed operations should not change the size")
+}
+
+func (suite *StackSuite) TestLIFO() {
+	stack := suite.NewStack()
+	for i := 0; i < 10; i++ {
+		assert.NoError(suite.T(), stack.PushHead(i), "PushHead should succeed on a new stack")
+		assert.Equal(suite.T(), i+1, stack.Size(), "PushHead: expected stack to be of size %d", i+1)
+		assert.False(suite.T(), stack.IsEmpty())
+	}
+	for i := 9; i >= 0; i-- {
+		item, err := stack.PopHead()
+		assert.NoError(suite.T(), err, "stack should contain items")
+		assert.Equal(suite.T(), i, item, "PushHead/PopHead should be LIFO")
+		assert.Equal(suite.T(), i, stack.Size(), "PopHead: expected stack to be of size %d", i)
+	}
+	assert.True(suite.T(), stack.IsEmpty())
+	_, err := stack.PopHead()
+	assert.IsType(suite.T(), lists.EmptyListError(""), err, "wanted EmptyListError")
+	stack.PushHead(10)
+	item, _ := stack.PopHead()
+	assert.Equal(suite.T(), 10, item, "stack should be reusable after being emptied")
+}
+
+func (suite *StackSuite) TestConcurrentPushPop() {
+	const goroutines, perGoroutine = 8, 500
+	stack := suite.NewStack()
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var popped []int
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			var mine []int
+			for i := 0; i < perGoroutine; i++ {
+				stack.PushHead(g*perGoroutine + i)
+				if i%2 == 1 {
+					if item, err := stack.PopHead(); err == nil {
+						mine = append(mine, item)
+					}
+				}
+			}
+			mu.Lock()
+			popped = append(popped, mine...)
+			mu.Unlock()
+		}(g)
+	}
+	wg.Wait()
+	for {
+		item, err := stack.PopHead()
+		if err != nil {
+			break
+		}
+		popped = append(popped, item)
+	}
+	sort.Ints(popped)
+	assert.Len(suite.T(), popped, goroutines*perGoroutine, "every item should be popped exactly once")
+	for i, item := range popped {
+		if !assert.Equal(suite.T(), i, item, "item %d was lost or duplicated", i) {
+			break
+		}
+	}
+	assert.Equal(suite.T(), 0, stack.Size())
+}
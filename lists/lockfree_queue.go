@@ -0,0 +1,136 @@
+package lists
+
+import "sync/atomic"
+
+// LockFreeQueue goroutine-safe FIFO queue based on the Michael-Scott
+// algorithm.  Producers and consumers use atomic compare-and-swap on
+// separate tail and head pointers instead of a lock, so any number of each
+// can work at the same time without blocking each other.  It implements
+// Queue, with PushTail and PopHead as aliases for Enqueue and Dequeue, so it
+// can replace a Doubly used as a queue by changing only the constructor.
+//
+// As with LockFreeStack, nodes are never reused, which rules out the ABA
+// problem.
+type LockFreeQueue[T any] struct {
+	// head is a sentinel node whose next node holds the front of the queue
+	head atomic.Pointer[queueNode[T]]
+	// tail is the last node in the queue, or lags one node behind it while
+	// an Enqueue is in progress
+	tail atomic.Pointer[queueNode[T]]
+	size atomic.Int64
+}
+
+type queueNode[T any] struct {
+	next atomic.Pointer[queueNode[T]]
+	data T
+}
+
+var _ Queue[interface{}] = (*LockFreeQueue[interface{}])(nil)
+
+// NewLockFreeQueue creates a new empty lock-free queue holding items of type
+// T
+func NewLockFreeQueue[T any]() *LockFreeQueue[T] {
+	q := &LockFreeQueue[T]{}
+	sentinel := &queueNode[T]{}
+	q.head.Store(sentinel)
+	q.tail.Store(sentinel)
+	return q
+}
+
+// Len is the number of items in the queue.  While other goroutines are
+// enqueuing or dequeuing the result is only a snapshot and may briefly lag
+// behind the items in the queue.
+//
+// Runtime: O(1)
+func (q *LockFreeQueue[T]) Len() int {
+	if size := q.size.Load(); size > 0 {
+		return int(size)
+	}
+	return 0
+}
+
+// Size is the same as Len
+//
+// Runtime: O(1)
+func (q *LockFreeQueue[T]) Size() int {
+	return q.Len()
+}
+
+// IsEmpty returns true if the queue contains no items
+//
+// Runtime: O(1)
+func (q *LockFreeQueue[T]) IsEmpty() bool {
+	return q.head.Load().next.Load() == nil
+}
+
+// Enqueue adds data to the back of the queue
+//
+// Runtime: O(1), retrying if another goroutine changes the tail first
+func (q *LockFreeQueue[T]) Enqueue(data T) {
+	node := &queueNode[T]{data: data}
+	for {
+		tail := q.tail.Load()
+		next := tail.next.Load()
+		if tail != q.tail.Load() {
+			continue
+		}
+		if next != nil {
+			// another Enqueue linked its node but hasn't swung the tail
+			// yet, so help it along
+			q.tail.CompareAndSwap(tail, next)
+			continue
+		}
+		if tail.next.CompareAndSwap(nil, node) {
+			q.tail.CompareAndSwap(tail, node)
+			q.size.Add(1)
+			return
+		}
+	}
+}
+
+// Dequeue removes data from the front of the queue.  Returns an
+// EmptyListError if there are no items in the queue.
+//
+// Runtime: O(1), retrying if another goroutine changes the head first
+func (q *LockFreeQueue[T]) Dequeue() (data T, err error) {
+	for {
+		head := q.head.Load()
+		tail := q.tail.Load()
+		next := head.next.Load()
+		if head != q.head.Load() {
+			continue
+		}
+		if next == nil {
+			return data, EmptyListError("can't remove an item from an empty list")
+		}
+		if head == tail {
+			// the tail is lagging behind an Enqueue in progress
+			q.tail.CompareAndSwap(tail, next)
+			continue
+		}
+		if q.head.CompareAndSwap(head, next) {
+			// next is the new sentinel.  Only the goroutine that moved the
+			// head to it reads its data, so the data can be cleared to
+			// let it be freed without racing another Dequeue.
+			data, next.data = next.data, data
+			q.size.Add(-1)
+			return data, nil
+		}
+	}
+}
+
+// PushTail is the same as Enqueue.  The queue is unbounded, so it always
+// returns nil.
+//
+// Runtime: O(1)
+func (q *LockFreeQueue[T]) PushTail(data T) error {
+	q.Enqueue(data)
+	return nil
+}
+
+// PopHead is the same as Dequeue
+//
+// Runtime: O(1)
+func (q *LockFreeQueue[T]) PopHead() (data T, err error) {
+	return q.Dequeue()
+}
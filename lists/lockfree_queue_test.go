@@ -0,0 +1,42 @@
+package lists
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// benchmarkQueue enqueues and dequeues from queue on every parallel goroutine
+func benchmarkQueue(b *testing.B, queue Queue[int]) {
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if i%2 == 0 {
+				queue.PushTail(i)
+			} else {
+				queue.PopHead()
+			}
+			i++
+		}
+	})
+}
+
+func TestLockFreeQueueLen(t *testing.T) {
+	queue := NewLockFreeQueue[string]()
+	queue.Enqueue("a")
+	queue.Enqueue("b")
+	assert.Equal(t, 2, queue.Len())
+	item, err := queue.Dequeue()
+	assert.NoError(t, err)
+	assert.Equal(t, "a", item)
+	assert.Equal(t, 1, queue.Len())
+	assert.Empty(t, queue.head.Load().data, "the sentinel should not keep the dequeued item")
+}
+
+func BenchmarkLockFreeQueue(b *testing.B) {
+	benchmarkQueue(b, NewLockFreeQueue[int]())
+}
+
+func BenchmarkDoublyQueue(b *testing.B) {
+	benchmarkQueue(b, NewDoublyOf[int]())
+}
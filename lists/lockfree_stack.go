@@ -0,0 +1,82 @@
+package lists
+
+import "sync/atomic"
+
+// LockFreeStack goroutine-safe LIFO stack that uses atomic compare-and-swap
+// on its head instead of a lock, so goroutines pushing and popping at the
+// same time never block each other.  It implements Stack and the zero value
+// is an empty stack ready to use.
+//
+// Every push allocates a new node and nodes are never reused, so a node can't
+// be freed and reallocated while another goroutine still holds a pointer to
+// it.  That rules out the ABA problem without tagged pointers or hazard
+// pointers.
+type LockFreeStack[T any] struct {
+	head atomic.Pointer[stackNode[T]]
+	size atomic.Int64
+}
+
+type stackNode[T any] struct {
+	// next is set before the node is published and never changes afterwards
+	next *stackNode[T]
+	data T
+}
+
+var _ Stack[interface{}] = (*LockFreeStack[interface{}])(nil)
+
+// NewLockFreeStack creates a new empty lock-free stack holding items of type
+// T
+func NewLockFreeStack[T any]() *LockFreeStack[T] {
+	return &LockFreeStack[T]{}
+}
+
+// Size of the stack.  While other goroutines are pushing or popping the
+// result is only a snapshot and may briefly lag behind the items on the
+// stack.
+//
+// Runtime: O(1)
+func (s *LockFreeStack[T]) Size() int {
+	if size := s.size.Load(); size > 0 {
+		return int(size)
+	}
+	return 0
+}
+
+// IsEmpty returns true if the stack contains no items
+//
+// Runtime: O(1)
+func (s *LockFreeStack[T]) IsEmpty() bool {
+	return s.head.Load() == nil
+}
+
+// PushHead adds data to the top of the stack.  The stack is unbounded, so it
+// always returns nil.
+//
+// Runtime: O(1), retrying if another goroutine changes the top first
+func (s *LockFreeStack[T]) PushHead(data T) error {
+	node := &stackNode[T]{data: data}
+	for {
+		node.next = s.head.Load()
+		if s.head.CompareAndSwap(node.next, node) {
+			s.size.Add(1)
+			return nil
+		}
+	}
+}
+
+// PopHead removes data from the top of the stack.  Returns an EmptyListError
+// if there are no items in the stack.
+//
+// Runtime: O(1), retrying if another goroutine changes the top first
+func (s *LockFreeStack[T]) PopHead() (data T, err error) {
+	for {
+		head := s.head.Load()
+		if head == nil {
+			return data, EmptyListError("can't remove an item from an empty list")
+		}
+		if s.head.CompareAndSwap(head, head.next) {
+			s.size.Add(-1)
+			return head.data, nil
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package lists
+
+import "testing"
+
+// benchmarkStack pushes and pops from stack on every parallel goroutine
+func benchmarkStack(b *testing.B, stack Stack[int]) {
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if i%2 == 0 {
+				stack.PushHead(i)
+			} else {
+				stack.PopHead()
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkLockFreeStack(b *testing.B) {
+	benchmarkStack(b, NewLockFreeStack[int]())
+}
+
+func BenchmarkSinglyStack(b *testing.B) {
+	benchmarkStack(b, NewSinglyOf[int]())
+}
+
+func BenchmarkLockFreeStackContended(b *testing.B) {
+	b.SetParallelism(8)
+	benchmarkStack(b, NewLockFreeStack[int]())
+}
+
+func BenchmarkSinglyStackContended(b *testing.B) {
+	b.SetParallelism(8)
+	benchmarkStack(b, NewSinglyOf[int]())
+}
@@ -0,0 +1,238 @@
+package lists
+
+import (
+	"context"
+	"reflect"
+)
+
+// End selects the front or the back of a list
+type End int
+
+const (
+	// Head is the front of a list
+	Head End = iota
+	// Tail is the back of a list
+	Tail
+)
+
+func (e End) opposite() End {
+	if e == Head {
+		return Tail
+	}
+	return Head
+}
+
+// Movable is a list that Move and MoveWait can take items from and add items
+// to.  It is implemented by *Singly and *Doubly and can't be implemented
+// outside this package.
+type Movable[T any] interface {
+	locker() rwLocker
+	state() (b *bounds, size *int, notEmpty *waitCond)
+	emptyError() error
+	popEnd(end End) T
+	pushEnd(end End, data T)
+}
+
+// Move atomically removes an item from srcEnd of src and adds it to dstEnd of
+// dst, so that no other goroutine can see the item in neither list or in
+// both.  src and dst may be the same list, in which case the item is moved
+// from one end to the other (or back to where it was).  Locks are taken in a
+// fixed order, so concurrent moves in opposite directions can't deadlock.
+//
+// Returns the item that was moved, an EmptyListError or DrainedListError if
+// src is empty, or a ClosedListError if dst has been closed.  If dst is full
+// its OverflowPolicy applies as for TryPushHead and TryPushTail, except that
+// the item is never lost: Evict removes an item from the opposite end of dst,
+// and Reject, Block and Drop return a CapacityExceededError and leave src
+// unchanged.
+//
+// Runtime: O(1), or O(n) to remove the tail of a Singly
+func Move[T any](src Movable[T], srcEnd End, dst Movable[T], dstEnd End) (data T, err error) {
+	first, second := lockPair(src, dst)
+	defer unlockPair(first, second)
+	return move(src, srcEnd, dst, dstEnd)
+}
+
+// MoveWait is like Move but waits for an item if src is empty and, if dst
+// has the Block policy, for room in dst.  It waits for an item in line with
+// PopHeadWait and PopTailWait callers on src.  The item stays in src while
+// waiting for room, and neither lock is held while waiting for the other
+// list.
+// Returns ctx.Err() if ctx is cancelled or its deadline passes before the
+// item is moved, a DrainedListError once src has been closed and emptied, or
+// a ClosedListError if dst has been closed, even while waiting.
+//
+// Runtime: O(1), or O(n) to remove the tail of a Singly
+func MoveWait[T any](ctx context.Context, src Movable[T], srcEnd End, dst Movable[T], dstEnd End) (data T, err error) {
+	// ticket is this goroutine's place in line for an item from src
+	var ticket uint64
+	for {
+		first, second := lockPair(src, dst)
+		srcBounds, srcSize, srcNotEmpty := src.state()
+		dstBounds, dstSize, _ := dst.state()
+		var cond *waitCond
+		var held, other rwLocker
+		switch {
+		case dstBounds.closed:
+			unlockPair(first, second)
+			return data, ClosedListError("can't add an item to a closed list")
+		case !srcBounds.closed && !srcNotEmpty.Ready(ticket, *srcSize):
+			// pass on any wakeup meant for a goroutine waiting for room in dst
+			if !dstBounds.full(*dstSize) {
+				dstBounds.notFull.Signal()
+			}
+			cond, held, other = srcNotEmpty, src.locker(), dst.locker()
+		case *srcSize > 0 && src != dst && !dstBounds.closed && dstBounds.policy == Block && dstBounds.full(*dstSize):
+			// pass on any wakeup meant for a goroutine waiting for an item
+			// in src, since this one isn't going to take it yet
+			srcNotEmpty.Signal()
+			cond, held, other = dstBounds.notFull, dst.locker(), src.locker()
+		default:
+			data, err = move(src, srcEnd, dst, dstEnd)
+			unlockPair(first, second)
+			return
+		}
+		if second != nil {
+			other.Unlock()
+		}
+		if cond == srcNotEmpty {
+			// closing dst stops the wait, since the item can't be moved
+			err = cond.WaitTurnOr(ctx, &ticket, dstBounds.closeCh)
+		} else if err = cond.Wait(ctx); err != nil {
+			dstBounds.stats.Rejected++
+		}
+		held.Unlock()
+		if err != nil {
+			return
+		}
+	}
+}
+
+// move does the work of Move.  The caller must hold the write locks of both
+// lists.
+func move[T any](src Movable[T], srcEnd End, dst Movable[T], dstEnd End) (data T, err error) {
+	if _, srcSize, _ := src.state(); *srcSize == 0 {
+		return data, src.emptyError()
+	}
+	dstBounds, dstSize, _ := dst.state()
+	if src == dst {
+		// the size doesn't change, so capacity doesn't matter
+		if dstBounds.closed {
+			return data, ClosedListError("can't add an item to a closed list")
+		}
+		data = src.popEnd(srcEnd)
+		dst.pushEnd(dstEnd, data)
+		return data, nil
+	}
+	if dstBounds.policy == Drop && !dstBounds.closed && dstBounds.full(*dstSize) {
+		// dropping would lose the item, which is still safe in src
+		dstBounds.stats.Rejected++
+		return data, CapacityExceededError("can't add an item to a full list")
+	}
+	if _, err = dstBounds.admit(context.Background(), false, dstSize, func() {
+		dst.popEnd(dstEnd.opposite())
+	}); err != nil {
+		return data, err
+	}
+	data = src.popEnd(srcEnd)
+	dst.pushEnd(dstEnd, data)
+	return data, nil
+}
+
+// lockPair takes the write locks of src and dst in a fixed order and returns
+// them in the order they were taken.  second is nil if both lists share a
+// lock.
+func lockPair[T any](src, dst Movable[T]) (first, second rwLocker) {
+	first, second = src.locker(), dst.locker()
+	if sameLocker(first, second) {
+		first.Lock()
+		return first, nil
+	}
+	if lockOrder(dst) < lockOrder(src) {
+		first, second = second, first
+	}
+	first.Lock()
+	second.Lock()
+	return
+}
+
+func unlockPair(first, second rwLocker) {
+	if second != nil {
+		second.Unlock()
+	}
+	first.Unlock()
+}
+
+// lockOrder returns the address of the lock guarding m, which is the same for
+// every list sharing it through WithLocker, or the address of m itself if the
+// lock isn't a pointer
+func lockOrder[T any](m Movable[T]) uintptr {
+	var l any = m.locker()
+	if e, ok := l.(exclusiveLocker); ok {
+		l = e.Locker
+	}
+	if v := reflect.ValueOf(l); v.Kind() == reflect.Pointer {
+		return v.Pointer()
+	}
+	return reflect.ValueOf(m).Pointer()
+}
+
+// sameLocker reports whether a and b are the same lock, so that taking both
+// would deadlock
+func sameLocker(a, b rwLocker) bool {
+	var x, y any = a, b
+	if e, ok := x.(exclusiveLocker); ok {
+		x = e.Locker
+	}
+	if e, ok := y.(exclusiveLocker); ok {
+		y = e.Locker
+	}
+	t := reflect.TypeOf(x)
+	return t == reflect.TypeOf(y) && t.Comparable() && x == y
+}
+
+func (s *Singly[T]) locker() rwLocker {
+	return s.rwLock
+}
+
+func (s *Singly[T]) state() (b *bounds, size *int, notEmpty *waitCond) {
+	return &s.bounds, &s.size, s.notEmpty
+}
+
+func (s *Singly[T]) popEnd(end End) T {
+	if end == Head {
+		return s.popHead()
+	}
+	return s.popTail()
+}
+
+func (s *Singly[T]) pushEnd(end End, data T) {
+	if end == Head {
+		s.pushHead(data)
+	} else {
+		s.pushTail(data)
+	}
+}
+
+func (d *Doubly[T]) locker() rwLocker {
+	return d.rwLock
+}
+
+func (d *Doubly[T]) state() (b *bounds, size *int, notEmpty *waitCond) {
+	return &d.bounds, &d.size, d.notEmpty
+}
+
+func (d *Doubly[T]) popEnd(end End) T {
+	if end == Head {
+		return d.popHead()
+	}
+	return d.popTail()
+}
+
+func (d *Doubly[T]) pushEnd(end End, data T) {
+	if end == Head {
+		d.pushHead(data)
+	} else {
+		d.pushTail(data)
+	}
+}
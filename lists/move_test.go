@@ -0,0 +1,210 @@
+package lists
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMove(t *testing.T) {
+	pending := NewDoublyOf[int]()
+	inFlight := NewDoublyOf[int]()
+	pending.PushTail(1)
+	pending.PushTail(2)
+
+	data, err := Move(pending, Head, inFlight, Tail)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, data)
+	data, err = Move(pending, Tail, inFlight, Head)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, data)
+	assert.True(t, pending.IsEmpty())
+	assert.Equal(t, []int{2, 1}, slices.Collect(inFlight.Values()))
+
+	_, err = Move(pending, Head, inFlight, Tail)
+	assert.IsType(t, EmptyListError(""), err)
+}
+
+func TestMoveBetweenSinglyAndDoubly(t *testing.T) {
+	s := NewSinglyOf[int]()
+	d := NewDoublyOf[int]()
+	s.PushTail(1)
+	s.PushTail(2)
+	Move[int](s, Tail, d, Tail)
+	Move[int](d, Head, s, Head)
+	assert.Equal(t, []int{2, 1}, slices.Collect(s.Values()))
+	assert.True(t, d.IsEmpty())
+}
+
+func TestMoveSameList(t *testing.T) {
+	d := NewBoundedDoubly[int](3, Reject)
+	d.PushTail(1)
+	d.PushTail(2)
+	d.PushTail(3)
+	data, err := Move(d, Head, d, Tail)
+	assert.NoError(t, err, "rotating a full list doesn't need room")
+	assert.Equal(t, 1, data)
+	assert.Equal(t, []int{2, 3, 1}, slices.Collect(d.Values()))
+	Move(d, Tail, d, Tail)
+	assert.Equal(t, []int{2, 3, 1}, slices.Collect(d.Values()))
+
+	s := NewSinglyOf[int]()
+	s.PushTail(1)
+	s.PushTail(2)
+	Move(s, Tail, s, Head)
+	assert.Equal(t, []int{2, 1}, slices.Collect(s.Values()))
+	s.Close()
+	_, err = Move(s, Head, s, Tail)
+	assert.IsType(t, ClosedListError(""), err)
+	assert.Equal(t, []int{2, 1}, slices.Collect(s.Values()))
+}
+
+func TestMoveClosedDestination(t *testing.T) {
+	src := NewDoublyOf[int]()
+	dst := NewDoublyOf[int]()
+	src.PushTail(1)
+	dst.Close()
+	_, err := Move(src, Head, dst, Tail)
+	assert.IsType(t, ClosedListError(""), err)
+	assert.Equal(t, 1, src.Size(), "the item should stay in src")
+
+	src.PopHead()
+	src.Close()
+	_, err = Move(src, Head, NewDoublyOf[int](), Tail)
+	assert.IsType(t, DrainedListError(""), err)
+}
+
+func TestMoveFullDestination(t *testing.T) {
+	for _, policy := range []OverflowPolicy{Reject, Block, Drop} {
+		src := NewDoublyOf[int]()
+		dst := NewBoundedDoubly[int](1, policy)
+		src.PushTail(1)
+		dst.PushTail(2)
+		_, err := Move(src, Head, dst, Tail)
+		assert.IsType(t, CapacityExceededError(""), err)
+		assert.Equal(t, 1, src.Size(), "the item should stay in src")
+		assert.Equal(t, uint64(1), dst.OverflowStats().Rejected)
+	}
+
+	src := NewDoublyOf[int]()
+	dst := NewBoundedDoubly[int](2, Evict)
+	src.PushTail(1)
+	dst.PushTail(2)
+	dst.PushTail(3)
+	Move(src, Head, dst, Tail)
+	assert.True(t, src.IsEmpty())
+	assert.Equal(t, []int{3, 1}, slices.Collect(dst.Values()))
+
+}
+
+func TestMoveConcurrentOppositeDirections(t *testing.T) {
+	var shared sync.Mutex
+	pairs := [][2]*Doubly[int]{
+		{NewDoublyOf[int](), NewDoublyOf[int]()},
+		{NewDoublyOf[int](WithLocker(&shared)), NewDoublyOf[int](WithLocker(&shared))},
+	}
+	for _, pair := range pairs {
+		a, b := pair[0], pair[1]
+		for i := 0; i < 100; i++ {
+			a.PushTail(i)
+			b.PushTail(i)
+		}
+		var wg sync.WaitGroup
+		for g := 0; g < 4; g++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 1000; i++ {
+					Move(a, Head, b, Tail)
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 1000; i++ {
+					Move(b, Tail, a, Head)
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, 200, a.Size()+b.Size(), "no item should be lost or duplicated")
+	}
+}
+
+func TestMoveWaitForItem(t *testing.T) {
+	src := NewSinglyOf[int]()
+	dst := NewDoublyOf[int]()
+	moved := make(chan int)
+	go func() {
+		data, _ := MoveWait[int](context.Background(), src, Head, dst, Tail)
+		moved <- data
+	}()
+	waitForWaiters(src.rwLock, src.notEmpty, 1)
+	src.PushTail(7)
+	assert.Equal(t, 7, <-moved)
+	assert.True(t, src.IsEmpty())
+	assert.Equal(t, []int{7}, slices.Collect(dst.Values()))
+}
+
+func TestMoveWaitForRoom(t *testing.T) {
+	src := NewDoublyOf[int]()
+	dst := NewBoundedDoubly[int](1, Block)
+	src.PushTail(1)
+	dst.PushTail(2)
+	moved := make(chan int)
+	go func() {
+		data, _ := MoveWait(context.Background(), src, Head, dst, Tail)
+		moved <- data
+	}()
+	waitForWaiters(dst.rwLock, dst.bounds.notFull, 1)
+	assert.Equal(t, 1, src.Size(), "the item should stay in src while waiting")
+	dst.PopHead()
+	assert.Equal(t, 1, <-moved)
+	assert.True(t, src.IsEmpty())
+	assert.Equal(t, []int{1}, slices.Collect(dst.Values()))
+}
+
+func TestMoveWaitCancel(t *testing.T) {
+	src := NewDoublyOf[int]()
+	dst := NewBoundedDoubly[int](1, Block)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := MoveWait(ctx, src, Head, dst, Tail)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	src.PushTail(1)
+	dst.PushTail(2)
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = MoveWait(ctx, src, Head, dst, Tail)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, 1, src.Size())
+
+	src.Close()
+	src.PopHead()
+	_, err = MoveWait(context.Background(), src, Head, dst, Tail)
+	assert.IsType(t, DrainedListError(""), err)
+}
+
+func TestMoveWaitClosedDestination(t *testing.T) {
+	src := NewSinglyOf[int]()
+	dst := NewDoublyOf[int]()
+	dst.Close()
+	_, err := MoveWait[int](context.Background(), src, Head, dst, Tail)
+	assert.IsType(t, ClosedListError(""), err, "an empty src shouldn't wait for a closed dst")
+
+	dst = NewDoublyOf[int]()
+	errs := make(chan error)
+	go func() {
+		_, err := MoveWait[int](context.Background(), src, Head, dst, Tail)
+		errs <- err
+	}()
+	waitForWaiters(src.rwLock, src.notEmpty, 1)
+	dst.Close()
+	assert.IsType(t, ClosedListError(""), <-errs, "closing dst should wake the mover")
+	src.PushTail(1)
+	assert.Equal(t, 1, src.Size(), "the item should stay in src")
+}
@@ -0,0 +1,76 @@
+package lists
+
+import "sync"
+
+// Option configures a Singly or Doubly when it is created
+type Option func(*options)
+
+type options struct {
+	rwLock rwLocker
+}
+
+// rwLocker is the lock guarding a Singly or Doubly.  *sync.RWMutex satisfies
+// it.
+type rwLocker interface {
+	sync.Locker
+	RLock()
+	RUnlock()
+}
+
+// WithLocker guards the list with l instead of a new sync.RWMutex, for
+// example to share one lock between several lists or to use an
+// instrumented lock.  If l also has RLock and RUnlock methods, like
+// *sync.RWMutex, readers use them; otherwise readers take the exclusive lock.
+func WithLocker(l sync.Locker) Option {
+	return func(o *options) {
+		if rw, ok := l.(rwLocker); ok {
+			o.rwLock = rw
+		} else {
+			o.rwLock = exclusiveLocker{l}
+		}
+	}
+}
+
+// WithoutLocking creates a list that does no locking at all, which removes
+// the cost of locking and unlocking from every call.  The list must then only
+// be used by one goroutine at a time, and PopHeadWait, PopTailWait,
+// PushHeadWait and PushTailWait must not be used to wait for an item or for
+// room, since nothing else can add or remove one while they wait.
+func WithoutLocking() Option {
+	return func(o *options) {
+		o.rwLock = noLocker{}
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.rwLock == nil {
+		o.rwLock = &sync.RWMutex{}
+	}
+	return o
+}
+
+// exclusiveLocker adapts a sync.Locker to rwLocker by using the exclusive
+// lock for readers too
+type exclusiveLocker struct {
+	sync.Locker
+}
+
+func (l exclusiveLocker) RLock() {
+	l.Lock()
+}
+
+func (l exclusiveLocker) RUnlock() {
+	l.Unlock()
+}
+
+// noLocker is an rwLocker that does nothing
+type noLocker struct{}
+
+func (noLocker) Lock()    {}
+func (noLocker) Unlock()  {}
+func (noLocker) RLock()   {}
+func (noLocker) RUnlock() {}
@@ -0,0 +1,67 @@
+package lists
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingLocker counts how often each lock method is called
+type countingLocker struct {
+	sync.Mutex
+	locks int
+}
+
+func (l *countingLocker) Lock() {
+	l.Mutex.Lock()
+	l.locks++
+}
+
+func TestWithLocker(t *testing.T) {
+	l := &countingLocker{}
+	list := NewDoublyOf[int](WithLocker(l))
+	list.PushHead(1)
+	list.Size()
+	assert.Equal(t, 2, l.locks, "readers should take the exclusive lock of a plain sync.Locker")
+
+	rw := &sync.RWMutex{}
+	shared := NewSinglyOf[int](WithLocker(rw))
+	assert.True(t, rw == shared.rwLock, "a sync.RWMutex should be used as is")
+	other := NewBoundedSingly[int](1, Reject, WithLocker(rw))
+	assert.True(t, rw == other.rwLock, "lists can share a lock")
+}
+
+func TestWithoutLocking(t *testing.T) {
+	list := NewSinglyOf[int](WithoutLocking())
+	assert.Equal(t, noLocker{}, list.rwLock)
+	list.PushTail(1)
+	for range list.All() {
+		assert.Equal(t, 1, list.Size(), "reads inside All are safe without a lock")
+	}
+}
+
+func benchmarkPushPop(b *testing.B, list List[int]) {
+	for i := 0; i < b.N; i++ {
+		list.PushTail(i)
+		list.PushHead(i)
+		list.PopHead()
+		list.PopTail()
+	}
+}
+
+func BenchmarkSinglyRWMutex(b *testing.B) {
+	benchmarkPushPop(b, NewSinglyOf[int]())
+}
+
+func BenchmarkSinglyWithoutLocking(b *testing.B) {
+	benchmarkPushPop(b, NewSinglyOf[int](WithoutLocking()))
+}
+
+func BenchmarkDoublyRWMutex(b *testing.B) {
+	benchmarkPushPop(b, NewDoublyOf[int]())
+}
+
+func BenchmarkDoublyWithoutLocking(b *testing.B) {
+	benchmarkPushPop(b, NewDoublyOf[int](WithoutLocking()))
+}
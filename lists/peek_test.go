@@ -0,0 +1,144 @@
+package lists
+
+import (
+	"iter"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// conditionalList is the part of Singly and Doubly exercised by
+// ConditionalTestSuite
+type conditionalList interface {
+	List[int]
+	Values() iter.Seq[int]
+	Close() error
+	PeekHead() (int, error)
+	PeekTail() (int, error)
+	PopHeadIf(pred func(data int) bool) (int, error)
+	PopTailIf(pred func(data int) bool) (int, error)
+	SwapHead(old, new int, eq func(a, b int) bool) bool
+}
+
+type ConditionalTestSuite struct {
+	suite.Suite
+	newList func() conditionalList
+}
+
+func TestConditionalTestSuite(t *testing.T) {
+	suite.Run(t, &ConditionalTestSuite{newList: func() conditionalList {
+		return NewSinglyOf[int]()
+	}})
+	suite.Run(t, &ConditionalTestSuite{newList: func() conditionalList {
+		return NewDoublyOf[int]()
+	}})
+}
+
+func intsEqual(a, b int) bool {
+	return a == b
+}
+
+func (suite *ConditionalTestSuite) TestPeek() {
+	list := suite.newList()
+	_, err := list.PeekHead()
+	assert.IsType(suite.T(), EmptyListError(""), err)
+	_, err = list.PeekTail()
+	assert.IsType(suite.T(), EmptyListError(""), err)
+
+	list.PushTail(1)
+	list.PushTail(2)
+	head, err := list.PeekHead()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, head)
+	tail, err := list.PeekTail()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, tail)
+	assert.Equal(suite.T(), 2, list.Size(), "peeking should not remove anything")
+}
+
+func (suite *ConditionalTestSuite) TestPopHeadIf() {
+	list := suite.newList()
+	_, err := list.PopHeadIf(func(int) bool { return true })
+	assert.IsType(suite.T(), EmptyListError(""), err)
+
+	list.PushTail(5)
+	list.PushTail(10)
+	_, err = list.PopHeadIf(func(data int) bool { return data > 5 })
+	assert.IsType(suite.T(), NoMatchError(""), err)
+	assert.Equal(suite.T(), 2, list.Size())
+
+	data, err := list.PopHeadIf(func(data int) bool { return data == 5 })
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 5, data)
+	assert.Equal(suite.T(), []int{10}, slices.Collect(list.Values()))
+
+	list.PopHead()
+	list.Close()
+	_, err = list.PopHeadIf(func(int) bool { return true })
+	assert.IsType(suite.T(), DrainedListError(""), err)
+}
+
+func (suite *ConditionalTestSuite) TestPopTailIf() {
+	list := suite.newList()
+	_, err := list.PopTailIf(func(int) bool { return true })
+	assert.IsType(suite.T(), EmptyListError(""), err)
+
+	list.PushTail(5)
+	list.PushTail(10)
+	_, err = list.PopTailIf(func(data int) bool { return data < 10 })
+	assert.IsType(suite.T(), NoMatchError(""), err)
+	assert.Equal(suite.T(), 2, list.Size())
+
+	data, err := list.PopTailIf(func(data int) bool { return data == 10 })
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 10, data)
+	assert.Equal(suite.T(), []int{5}, slices.Collect(list.Values()))
+	tail, _ := list.PeekTail()
+	assert.Equal(suite.T(), 5, tail)
+}
+
+func (suite *ConditionalTestSuite) TestSwapHead() {
+	list := suite.newList()
+	assert.False(suite.T(), list.SwapHead(0, 1, intsEqual))
+
+	list.PushTail(1)
+	list.PushTail(2)
+	assert.False(suite.T(), list.SwapHead(2, 3, intsEqual))
+	assert.True(suite.T(), list.SwapHead(1, 3, intsEqual))
+	assert.Equal(suite.T(), []int{3, 2}, slices.Collect(list.Values()))
+}
+
+func (suite *ConditionalTestSuite) TestPopHeadIfConcurrent() {
+	// every goroutine tries to pop the head if it is the item it expects, so
+	// each item should be popped exactly once no matter how they interleave
+	list := suite.newList()
+	for i := 0; i < 100; i++ {
+		list.PushTail(i)
+	}
+	popped := make([]int, 100)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			deadline := time.Now().Add(5 * time.Second)
+			for !list.IsEmpty() && time.Now().Before(deadline) {
+				head, err := list.PeekHead()
+				if err != nil {
+					return
+				}
+				if data, err := list.PopHeadIf(func(data int) bool { return data == head }); err == nil {
+					popped[data]++
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	for i, n := range popped {
+		assert.Equal(suite.T(), 1, n, "item %d", i)
+	}
+}
@@ -0,0 +1,258 @@
+package lists
+
+import (
+	"context"
+	"iter"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// sharedList is the part of Singly and Doubly exercised by SharedTestSuite
+type sharedList interface {
+	List[string]
+	All() iter.Seq2[int, string]
+	Values() iter.Seq[string]
+	Enumerate() iter.Seq2[int, string]
+	Get(i int) (data string, err error)
+	Set(i int, data string) error
+	InsertAt(i int, data string) error
+	RemoveAt(i int) (data string, err error)
+	PopHeadWait(ctx context.Context) (data string, err error)
+	PopTailWait(ctx context.Context) (data string, err error)
+}
+
+// SharedTestSuite holds the tests that Singly and Doubly must both pass, and
+// is run once for each of them.  Tests of behaviour only one of them has stay
+// in SinglyTestSuite or DoublyTestSuite.
+type SharedTestSuite struct {
+	suite.Suite
+	newList func() sharedList
+	data    []string
+}
+
+func TestSharedTestSuite(t *testing.T) {
+	t.Run("Singly", func(t *testing.T) {
+		suite.Run(t, &SharedTestSuite{newList: func() sharedList { return NewSinglyOf[string]() }})
+	})
+	t.Run("Doubly", func(t *testing.T) {
+		suite.Run(t, &SharedTestSuite{newList: func() sharedList { return NewDoublyOf[string]() }})
+	})
+}
+
+func (suite *SharedTestSuite) SetupTest() {
+	suite.data = []string{"data1", "data2", "something", "hello there world", "another thing"}
+}
+
+// waitForPoppers waits until n goroutines are waiting in PopHeadWait or
+// PopTailWait on list
+func (suite *SharedTestSuite) waitForPoppers(list sharedList, n int) {
+	switch l := list.(type) {
+	case *Singly[string]:
+		waitForWaiters(l.rwLock, l.notEmpty, n)
+	case *Doubly[string]:
+		waitForWaiters(l.rwLock, l.notEmpty, n)
+	}
+}
+
+// fill adds the suite's data to the back of list
+func (suite *SharedTestSuite) fill(list sharedList) {
+	for _, item := range suite.data {
+		list.PushTail(item)
+	}
+}
+
+func (suite *SharedTestSuite) TestTyped() {
+	list := suite.newList()
+	_, err := list.PopHead()
+	assert.Exactly(suite.T(), EmptyListError("can't remove an item from an empty list"), err, "wanted EmptyListError")
+	for _, item := range []string{"a", "b", "c", "d", "e"} {
+		list.PushTail(item)
+	}
+	assert.True(suite.T(), list.Contains(func(data string) bool { return data == "d" }))
+	numDeleted := list.Delete(0, func(data string) bool { return data == "b" || data == "d" })
+	assert.Equal(suite.T(), 2, numDeleted, "list should delete 2 items")
+	head, err := list.PopHead()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "a", head)
+	tail, err := list.PopTail()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "e", tail)
+	assert.Equal(suite.T(), 1, list.Size(), "list should have 1 item")
+}
+
+func (suite *SharedTestSuite) TestPopTailUnlinks() {
+	list := suite.newList()
+	suite.fill(list)
+	list.PopTail()
+	assert.False(suite.T(), list.Contains(func(data string) bool { return data == suite.data[len(suite.data)-1] }), "popped item should be unreachable")
+	list.PushTail("new tail")
+	item, err := list.PopTail()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "new tail", item)
+}
+
+func (suite *SharedTestSuite) TestAll() {
+	list := suite.newList()
+	for range list.All() {
+		suite.T().Fatal("empty list should not yield anything")
+	}
+	suite.fill(list)
+	n := 0
+	for i, item := range list.All() {
+		assert.Equal(suite.T(), n, i, "index is incorrect")
+		assert.Exactly(suite.T(), suite.data[i], item, "list item data[%d] is incorrect", i)
+		n++
+	}
+	assert.Equal(suite.T(), len(suite.data), n)
+	for i := range list.All() {
+		if i == 1 {
+			break
+		}
+	}
+	list.PushHead("lock released")
+	assert.Equal(suite.T(), len(suite.data)+1, list.Size())
+}
+
+func (suite *SharedTestSuite) TestValues() {
+	list := suite.newList()
+	suite.fill(list)
+	var items []string
+	for item := range list.Values() {
+		items = append(items, item)
+	}
+	assert.Equal(suite.T(), suite.data, items)
+}
+
+func (suite *SharedTestSuite) TestEnumerate() {
+	list := suite.newList()
+	suite.fill(list)
+	for i, item := range list.Enumerate() {
+		assert.Exactly(suite.T(), suite.data[i], item, "list item data[%d] is incorrect", i)
+		list.PopHead()
+		list.PushTail("added during loop")
+	}
+	assert.Equal(suite.T(), len(suite.data), list.Size())
+	assert.False(suite.T(), list.Contains(func(data string) bool { return data == suite.data[0] }))
+}
+
+func (suite *SharedTestSuite) TestGetSet() {
+	list := suite.newList()
+	_, err := list.Get(0)
+	assert.Exactly(suite.T(), IndexOutOfRangeError("index 0 out of range for list of size 0"), err)
+	suite.fill(list)
+	for i := range suite.data {
+		item, err := list.Get(i)
+		assert.NoError(suite.T(), err)
+		assert.Exactly(suite.T(), suite.data[i], item, "list item data[%d] is incorrect", i)
+	}
+	for _, i := range []int{-1, len(suite.data)} {
+		_, err = list.Get(i)
+		assert.IsType(suite.T(), IndexOutOfRangeError(""), err, "Get(%d)", i)
+		assert.IsType(suite.T(), IndexOutOfRangeError(""), list.Set(i, "x"), "Set(%d)", i)
+	}
+	for i := range suite.data {
+		assert.NoError(suite.T(), list.Set(i, suite.data[len(suite.data)-1-i]))
+	}
+	for i := range suite.data {
+		item, _ := list.PopTail()
+		assert.Exactly(suite.T(), suite.data[i], item, "list item data[%d] is incorrect", i)
+	}
+}
+
+func (suite *SharedTestSuite) TestInsertAt() {
+	list := suite.newList()
+	assert.IsType(suite.T(), IndexOutOfRangeError(""), list.InsertAt(1, "0"))
+	assert.NoError(suite.T(), list.InsertAt(0, "1"))
+	assert.NoError(suite.T(), list.InsertAt(1, "4"))
+	assert.NoError(suite.T(), list.InsertAt(0, "0"))
+	assert.NoError(suite.T(), list.InsertAt(2, "2"))
+	assert.NoError(suite.T(), list.InsertAt(3, "3"))
+	assert.NoError(suite.T(), list.InsertAt(5, "5"))
+	assert.IsType(suite.T(), IndexOutOfRangeError(""), list.InsertAt(7, "7"))
+	assert.IsType(suite.T(), IndexOutOfRangeError(""), list.InsertAt(-1, "7"))
+	assert.Equal(suite.T(), 6, list.Size())
+	list.PushTail("6")
+	for i, item := range list.All() {
+		assert.Equal(suite.T(), strconv.Itoa(i), item, "list item %d is incorrect", i)
+	}
+}
+
+func (suite *SharedTestSuite) TestRemoveAt() {
+	list := suite.newList()
+	_, err := list.RemoveAt(0)
+	assert.IsType(suite.T(), IndexOutOfRangeError(""), err)
+	for i := 0; i < 8; i++ {
+		list.PushTail(strconv.Itoa(i))
+	}
+	_, err = list.RemoveAt(8)
+	assert.IsType(suite.T(), IndexOutOfRangeError(""), err)
+	for _, c := range []struct {
+		index int
+		item  string
+	}{{0, "0"}, {6, "7"}, {3, "4"}, {4, "6"}, {1, "2"}} {
+		item, err := list.RemoveAt(c.index)
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), c.item, item, "RemoveAt(%d) is incorrect", c.index)
+	}
+	assert.Equal(suite.T(), 3, list.Size())
+	list.PushTail("8")
+	var items []string
+	for item := range list.Values() {
+		items = append(items, item)
+	}
+	assert.Equal(suite.T(), []string{"1", "3", "5", "8"}, items)
+}
+
+func (suite *SharedTestSuite) TestPopWait() {
+	list := suite.newList()
+	list.PushTail(suite.data[0])
+	list.PushTail(suite.data[1])
+	item, err := list.PopHeadWait(context.Background())
+	assert.NoError(suite.T(), err)
+	assert.Exactly(suite.T(), suite.data[0], item, "PopHeadWait should not wait when the list has items")
+	item, err = list.PopTailWait(context.Background())
+	assert.NoError(suite.T(), err)
+	assert.Exactly(suite.T(), suite.data[1], item, "PopTailWait should not wait when the list has items")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = list.PopHeadWait(ctx)
+	assert.Equal(suite.T(), context.DeadlineExceeded, err)
+	_, err = list.PopTailWait(ctx)
+	assert.Equal(suite.T(), context.DeadlineExceeded, err)
+	list.PushHead(suite.data[2])
+	assert.Equal(suite.T(), 1, list.Size(), "a timed out wait should not consume a later item")
+}
+
+func (suite *SharedTestSuite) TestPopWaitWakes() {
+	list := suite.newList()
+	const waiters = 4
+	results := make(chan string, waiters)
+	for i := 0; i < waiters; i++ {
+		go func(i int) {
+			var item string
+			var err error
+			if i%2 == 0 {
+				item, err = list.PopHeadWait(context.Background())
+			} else {
+				item, err = list.PopTailWait(context.Background())
+			}
+			assert.NoError(suite.T(), err)
+			results <- item
+		}(i)
+	}
+	suite.waitForPoppers(list, waiters)
+	for i := 0; i < waiters; i++ {
+		list.PushTail(strconv.Itoa(i))
+	}
+	seen := map[string]bool{}
+	for i := 0; i < waiters; i++ {
+		seen[<-results] = true
+	}
+	assert.Len(suite.T(), seen, waiters, "every waiter should get a different item")
+	assert.True(suite.T(), list.IsEmpty())
+}
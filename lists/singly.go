@@ -1,34 +1,75 @@
 package lists
 
-import "sync"
+import (
+	"context"
+	"fmt"
+	"iter"
+)
 
-// Singly goroutine-safe implementation of a singly-linked list
-type Singly struct {
-	head   *singlyNode
-	tail   *singlyNode
+// Singly goroutine-safe implementation of a singly-linked list holding
+// items of type T
+type Singly[T any] struct {
+	head   *singlyNode[T]
+	tail   *singlyNode[T]
 	size   int
-	rwLock *sync.RWMutex
+	rwLock rwLocker
+	// notEmpty is signaled once for every item added to the list
+	notEmpty *waitCond
+	bounds   bounds
+	// opts are the options the list was created with, reused for the lists
+	// returned by SplitAt and SplitWhere
+	opts []Option
+	// tx is the transaction running in Do, if any, which records how to undo
+	// every change made to the list
+	tx *singlyTx[T]
 }
 
-type singlyNode struct {
-	Next *singlyNode
-	Data interface{}
+type singlyNode[T any] struct {
+	Next *singlyNode[T]
+	Data T
 }
 
-// NewSingly creates a new empty singly-linked list
-func NewSingly() *Singly {
-	return &Singly{
-		head:   nil,
-		tail:   nil,
-		size:   0,
-		rwLock: &sync.RWMutex{},
+// NewSingly creates a new empty singly-linked list that can hold any type of
+// data.  Use NewSinglyOf to create a list of a specific type.
+func NewSingly(opts ...Option) *UntypedSingly {
+	return &UntypedSingly{NewSinglyOf[interface{}](opts...)}
+}
+
+// NewSinglyOf creates a new empty singly-linked list holding items of type T.
+// By default the list is guarded by a sync.RWMutex; see WithLocker and
+// WithoutLocking for alternatives.
+func NewSinglyOf[T any](opts ...Option) *Singly[T] {
+	return newSingly[T](bounds{}, opts)
+}
+
+// NewBoundedSingly creates a new empty singly-linked list holding at most
+// capacity items of type T.  policy decides what happens to items pushed while
+// the list is full.  opts work as they do for NewSinglyOf.  Panics if capacity
+// is less than 1.
+func NewBoundedSingly[T any](capacity int, policy OverflowPolicy, opts ...Option) *Singly[T] {
+	return newSingly[T](newBounds(capacity, policy), opts)
+}
+
+func newSingly[T any](b bounds, opts []Option) *Singly[T] {
+	rwLock := newOptions(opts).rwLock
+	b.notFull = newWaitCond(rwLock)
+	b.done = make(chan struct{})
+	b.closeCh = make(chan struct{})
+	return &Singly[T]{
+		head:     nil,
+		tail:     nil,
+		size:     0,
+		rwLock:   rwLock,
+		notEmpty: newWaitCond(rwLock),
+		bounds:   b,
+		opts:     opts,
 	}
 }
 
 // Size of the list
 //
 // Runtime: O(1)
-func (s *Singly) Size() int {
+func (s *Singly[T]) Size() int {
 	s.rwLock.RLock()
 	defer s.rwLock.RUnlock()
 	return s.size
@@ -37,99 +78,289 @@ func (s *Singly) Size() int {
 // IsEmpty returns true if the list contains no items
 //
 // Runtime: O(1)
-func (s *Singly) IsEmpty() bool {
+func (s *Singly[T]) IsEmpty() bool {
 	s.rwLock.RLock()
 	defer s.rwLock.RUnlock()
 	return s.head == nil
 }
 
-// PushHead adds data to the front of the list
+// PushHead adds data to the front of the list.  On a bounded list that is
+// full, the list's OverflowPolicy applies: Reject returns a
+// CapacityExceededError, Block waits for room, Evict removes the tail and
+// Drop discards the item without an error.  Returns a ClosedListError if the
+// list has been closed.
+//
+// Runtime: O(1), or O(n) if the Evict policy removes the tail
+func (s *Singly[T]) PushHead(data T) error {
+	s.rwLock.Lock()
+	defer s.rwLock.Unlock()
+	return s.push(context.Background(), true, data, true)
+}
+
+// PushTail adds data to the back of the list.  On a bounded list that is
+// full, the list's OverflowPolicy applies: Reject returns a
+// CapacityExceededError, Block waits for room, Evict removes the head and
+// Drop discards the item without an error.  Returns a ClosedListError if the
+// list has been closed.
+//
+// Runtime: O(1)
+func (s *Singly[T]) PushTail(data T) error {
+	s.rwLock.Lock()
+	defer s.rwLock.Unlock()
+	return s.push(context.Background(), true, data, false)
+}
+
+// TryPushHead adds data to the front of the list without waiting.  Returns a
+// CapacityExceededError if the list is full and its policy is Reject or
+// Block, or a ClosedListError if the list has been closed.
+//
+// Runtime: O(1), or O(n) if the Evict policy removes the tail
+func (s *Singly[T]) TryPushHead(data T) error {
+	s.rwLock.Lock()
+	defer s.rwLock.Unlock()
+	return s.push(context.Background(), false, data, true)
+}
+
+// TryPushTail adds data to the back of the list without waiting.  Returns a
+// CapacityExceededError if the list is full and its policy is Reject or
+// Block, or a ClosedListError if the list has been closed.
 //
 // Runtime: O(1)
-func (s *Singly) PushHead(data interface{}) {
+func (s *Singly[T]) TryPushTail(data T) error {
+	s.rwLock.Lock()
+	defer s.rwLock.Unlock()
+	return s.push(context.Background(), false, data, false)
+}
+
+// PushHeadWait adds data to the front of the list.  If the list is full and
+// its policy is Block, it waits for room and returns ctx.Err() if ctx is
+// done first.  Returns a CapacityExceededError if the list is full and its
+// policy is Reject, or a ClosedListError if the list has been closed, even
+// while waiting.
+//
+// Runtime: O(1), or O(n) if the Evict policy removes the tail
+func (s *Singly[T]) PushHeadWait(ctx context.Context, data T) error {
+	s.rwLock.Lock()
+	defer s.rwLock.Unlock()
+	return s.push(ctx, true, data, true)
+}
+
+// PushTailWait adds data to the back of the list.  If the list is full and
+// its policy is Block, it waits for room and returns ctx.Err() if ctx is
+// done first.  Returns a CapacityExceededError if the list is full and its
+// policy is Reject, or a ClosedListError if the list has been closed, even
+// while waiting.
+//
+// Runtime: O(1)
+func (s *Singly[T]) PushTailWait(ctx context.Context, data T) error {
+	s.rwLock.Lock()
+	defer s.rwLock.Unlock()
+	return s.push(ctx, true, data, false)
+}
+
+// Capacity returns the maximum number of items the list can hold, or 0 if
+// the list is unbounded
+//
+// Runtime: O(1)
+func (s *Singly[T]) Capacity() int {
+	return s.bounds.capacity
+}
+
+// OverflowStats returns how many items the list's OverflowPolicy has
+// rejected, evicted or dropped so far
+//
+// Runtime: O(1)
+func (s *Singly[T]) OverflowStats() OverflowStats {
+	s.rwLock.RLock()
+	defer s.rwLock.RUnlock()
+	return s.bounds.stats
+}
+
+// PopHead removes data from the front of the list.  Returns an
+// EmptyListError if there are no items in the list, or a DrainedListError if
+// the list has also been closed.
+//
+// Runtime: O(1)
+func (s *Singly[T]) PopHead() (data T, err error) {
 	s.rwLock.Lock()
 	defer s.rwLock.Unlock()
 	if s.head == nil {
-		s.head = &singlyNode{Next: nil, Data: data}
-		s.tail = s.head
-	} else {
-		next := s.head
-		s.head = &singlyNode{Next: next, Data: data}
+		return data, s.emptyError()
 	}
-	s.size++
+	return s.popHead(), nil
 }
 
-// PushTail adds data to the back of the list
+// PopTail removes data from the back of the list.  Returns an
+// EmptyListError if there are no items in the list, or a DrainedListError if
+// the list has also been closed.
 //
-// Runtime: O(1)
-func (s *Singly) PushTail(data interface{}) {
+// Runtime: O(n)
+func (s *Singly[T]) PopTail() (data T, err error) {
 	s.rwLock.Lock()
 	defer s.rwLock.Unlock()
 	if s.head == nil {
-		s.head = &singlyNode{Next: nil, Data: data}
-		s.tail = s.head
-	} else {
-		s.tail.Next = &singlyNode{Next: nil, Data: data}
-		s.tail = s.tail.Next
+		return data, s.emptyError()
 	}
-	s.size++
+	return s.popTail(), nil
 }
 
-// PopHead removes data from the front of the list.  Returns an
-// EmptyListError if there are no items in the list.
+// PopHeadWait removes data from the front of the list, waiting for an item
+// to be added if the list is empty.  Goroutines waiting on the same list are
+// handed items in the order they started waiting, whichever end they pop
+// from.  PopHead, PopTail and other methods that don't wait can still take
+// an item meant for a waiting goroutine, which then keeps its place at the
+// front of the line.  Returns ctx.Err() if ctx is cancelled or its deadline
+// passes before an item is available, or a DrainedListError once the list
+// has been closed and emptied.
 //
 // Runtime: O(1)
-func (s *Singly) PopHead() (data interface{}, err error) {
+func (s *Singly[T]) PopHeadWait(ctx context.Context) (data T, err error) {
+	s.rwLock.Lock()
+	defer s.rwLock.Unlock()
+	if err = s.waitNotEmpty(ctx); err != nil {
+		return
+	}
+	return s.popHead(), nil
+}
+
+// PopTailWait removes data from the back of the list, waiting for an item to
+// be added if the list is empty.  Waiting goroutines are handed items in
+// the order they started waiting, as for PopHeadWait.  Returns ctx.Err() if
+// ctx is cancelled or its deadline passes before an item is available, or a
+// DrainedListError once the list has been closed and emptied.
+//
+// Runtime: O(n)
+func (s *Singly[T]) PopTailWait(ctx context.Context) (data T, err error) {
 	s.rwLock.Lock()
 	defer s.rwLock.Unlock()
+	if err = s.waitNotEmpty(ctx); err != nil {
+		return
+	}
+	return s.popTail(), nil
+}
+
+// PeekHead returns the data at the front of the list without removing it.
+// Returns an EmptyListError if there are no items in the list.
+//
+// Runtime: O(1)
+func (s *Singly[T]) PeekHead() (data T, err error) {
+	s.rwLock.RLock()
+	defer s.rwLock.RUnlock()
 	if s.head == nil {
-		return "", EmptyListError("can't remove an item from an empty list")
+		return data, EmptyListError("can't peek at an empty list")
+	}
+	return s.head.Data, nil
+}
+
+// PeekTail returns the data at the back of the list without removing it.
+// Returns an EmptyListError if there are no items in the list.
+//
+// Runtime: O(1)
+func (s *Singly[T]) PeekTail() (data T, err error) {
+	s.rwLock.RLock()
+	defer s.rwLock.RUnlock()
+	if s.tail == nil {
+		return data, EmptyListError("can't peek at an empty list")
 	}
-	data = s.head.Data
-	s.head = s.head.Next
+	return s.tail.Data, nil
+}
+
+// PopHeadIf removes data from the front of the list only if pred returns true
+// for it.  The check and the removal happen under a single lock, so no other
+// goroutine can change the head in between.  Returns a NoMatchError if pred
+// returns false, an EmptyListError if there are no items in the list, or a
+// DrainedListError if the list has also been closed.
+//
+// Runtime: O(1)
+func (s *Singly[T]) PopHeadIf(pred func(data T) bool) (data T, err error) {
+	s.rwLock.Lock()
+	defer s.rwLock.Unlock()
 	if s.head == nil {
-		s.tail = nil
+		return data, s.emptyError()
 	}
-	s.size--
-	return
+	if !pred(s.head.Data) {
+		return data, NoMatchError("item at the head doesn't match")
+	}
+	return s.popHead(), nil
 }
 
-// PopTail removes data from the back of the list.  Returns an
-// EmptyListError if there are no items in the list.
+// PopTailIf removes data from the back of the list only if pred returns true
+// for it.  The check and the removal happen under a single lock, so no other
+// goroutine can change the tail in between.  Returns a NoMatchError if pred
+// returns false, an EmptyListError if there are no items in the list, or a
+// DrainedListError if the list has also been closed.
 //
 // Runtime: O(n)
-func (s *Singly) PopTail() (data interface{}, err error) {
+func (s *Singly[T]) PopTailIf(pred func(data T) bool) (data T, err error) {
 	s.rwLock.Lock()
 	defer s.rwLock.Unlock()
-	var tmp *singlyNode
-	if s.head == nil {
-		return "", EmptyListError("can't remove an item from an empty list")
+	if s.tail == nil {
+		return data, s.emptyError()
 	}
-	data = s.tail.Data
-	if s.head == s.tail {
-		s.head, s.tail = nil, nil
-	} else {
-		for tmp = s.head; tmp.Next != s.tail; tmp = tmp.Next {
-		}
-		s.tail = tmp
+	if !pred(s.tail.Data) {
+		return data, NoMatchError("item at the tail doesn't match")
 	}
-	s.size--
-	return
+	return s.popTail(), nil
+}
+
+// SwapHead replaces the data at the front of the list with new, but only if
+// eq reports that it is equal to old, like a compare-and-swap.  Returns
+// whether the data was replaced, which is false if the list is empty.
+//
+// Runtime: O(1)
+func (s *Singly[T]) SwapHead(old, new T, eq func(a, b T) bool) (swapped bool) {
+	s.rwLock.Lock()
+	defer s.rwLock.Unlock()
+	if s.head == nil || !eq(s.head.Data, old) {
+		return false
+	}
+	s.head.Data = new
+	return true
+}
+
+// Close stops any more items from being added to the list: every push
+// returns a ClosedListError from then on.  Items already in the list can
+// still be removed, and once they have all gone every pop returns a
+// DrainedListError.  Goroutines waiting in PopHeadWait or PopTailWait on an
+// empty list, or waiting for room in PushHeadWait or PushTailWait, are woken.
+// Returns a ClosedListError if the list was already closed.
+//
+// Runtime: O(1)
+func (s *Singly[T]) Close() error {
+	s.rwLock.Lock()
+	defer s.rwLock.Unlock()
+	if err := s.bounds.close(s.size); err != nil {
+		return err
+	}
+	s.notEmpty.Broadcast()
+	return nil
+}
+
+// IsClosed returns true if Close has been called
+//
+// Runtime: O(1)
+func (s *Singly[T]) IsClosed() bool {
+	s.rwLock.RLock()
+	defer s.rwLock.RUnlock()
+	return s.bounds.closed
+}
+
+// Done returns a channel that is closed once the list has been closed and
+// every item has been removed from it
+//
+// Runtime: O(1)
+func (s *Singly[T]) Done() <-chan struct{} {
+	return s.bounds.done
 }
 
 // Contains returns true if list contains any data where the comparison
 // function returns true.  Moves from the head of the list to the tail.
 //
 // Runtime: O(n)
-func (s *Singly) Contains(comparison func(data interface{}) (exists bool)) bool {
+func (s *Singly[T]) Contains(comparison func(data T) (exists bool)) bool {
 	s.rwLock.RLock()
 	defer s.rwLock.RUnlock()
-	for tmp := s.head; tmp != nil; tmp = tmp.Next {
-		if comparison(tmp.Data) {
-			return true
-		}
-	}
-	return false
+	return s.contains(comparison)
 }
 
 // Delete numItems data in the list based on the provided comparison function.
@@ -139,31 +370,279 @@ func (s *Singly) Contains(comparison func(data interface{}) (exists bool)) bool
 // then all data in the list is scanned.
 //
 // Runtime: O(n)
-func (s *Singly) Delete(numItems int, comparison func(data interface{}) (shouldDelete bool)) (numDeleted int) {
+func (s *Singly[T]) Delete(numItems int, comparison func(data T) (shouldDelete bool)) (numDeleted int) {
 	s.rwLock.Lock()
 	defer s.rwLock.Unlock()
-	if s.head == nil {
-		return
+	return s.deleteMatches(numItems, comparison)
+}
+
+// Get returns the data at index i, counting from 0 at the head of the list.
+// Returns an IndexOutOfRangeError if i is not in [0, Size()).
+//
+// Runtime: O(n)
+func (s *Singly[T]) Get(i int) (data T, err error) {
+	s.rwLock.RLock()
+	defer s.rwLock.RUnlock()
+	return s.get(i)
+}
+
+// Set replaces the data at index i.  Returns an IndexOutOfRangeError if i is
+// not in [0, Size()).
+//
+// Runtime: O(n)
+func (s *Singly[T]) Set(i int, data T) error {
+	s.rwLock.Lock()
+	defer s.rwLock.Unlock()
+	return s.set(i, data)
+}
+
+// InsertAt adds data at index i, moving the item previously at i and every
+// item after it one position towards the tail.  i may equal Size() to add
+// data to the back of the list.  Returns an IndexOutOfRangeError if i is not
+// in [0, Size()].  On a bounded list that is full, the Drop policy discards
+// data and every other policy returns a CapacityExceededError.
+//
+// Runtime: O(n)
+func (s *Singly[T]) InsertAt(i int, data T) error {
+	s.rwLock.Lock()
+	defer s.rwLock.Unlock()
+	return s.insertAt(i, data)
+}
+
+// RemoveAt removes the data at index i.  Returns an IndexOutOfRangeError if i
+// is not in [0, Size()).
+//
+// Runtime: O(n)
+func (s *Singly[T]) RemoveAt(i int) (data T, err error) {
+	s.rwLock.Lock()
+	defer s.rwLock.Unlock()
+	return s.removeAt(i)
+}
+
+// All returns an iterator over the index and data of every item from the
+// head of the list to the tail.  The read lock is held for the whole loop, so
+// the loop body must not call other methods on the list; use Enumerate if it
+// needs to.
+//
+// Runtime: O(n)
+func (s *Singly[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		s.rwLock.RLock()
+		defer s.rwLock.RUnlock()
+		i := 0
+		for tmp := s.head; tmp != nil; tmp = tmp.Next {
+			if !yield(i, tmp.Data) {
+				return
+			}
+			i++
+		}
 	}
-	for comparison(s.head.Data) {
-		numDeleted++
-		s.size--
-		if s.head == s.tail {
-			s.head, s.tail = nil, nil
-			return
+}
+
+// Values returns an iterator over the data of every item from the head of the
+// list to the tail.  Like All, the read lock is held for the whole loop.
+//
+// Runtime: O(n)
+func (s *Singly[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s.rwLock.RLock()
+		defer s.rwLock.RUnlock()
+		for tmp := s.head; tmp != nil; tmp = tmp.Next {
+			if !yield(tmp.Data) {
+				return
+			}
 		}
-		s.head = s.head.Next
-		if numItems == numDeleted {
-			return
+	}
+}
+
+// Enumerate returns an iterator over the index and data of every item from
+// the head of the list to the tail.  Each iteration works from a snapshot of
+// the list taken under the read lock when the loop starts, so the lock is not
+// held while the loop body runs and the body may modify the list.  Changes
+// made during the loop are not seen by it.
+//
+// Runtime: O(n), plus O(n) memory for the snapshot
+func (s *Singly[T]) Enumerate() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, data := range s.snapshot() {
+			if !yield(i, data) {
+				return
+			}
 		}
 	}
-	for pred, tmp := s.head, s.head.Next; tmp != nil; tmp = tmp.Next {
-		if comparison(tmp.Data) {
-			pred.Next = tmp.Next
-			if pred.Next == nil {
-				s.tail = pred
+}
+
+// snapshot copies the data in the list, from head to tail, into a slice
+func (s *Singly[T]) snapshot() []T {
+	s.rwLock.RLock()
+	defer s.rwLock.RUnlock()
+	data := make([]T, 0, s.size)
+	for tmp := s.head; tmp != nil; tmp = tmp.Next {
+		data = append(data, tmp.Data)
+	}
+	return data
+}
+
+// push adds data to the front or back of the list after applying the
+// overflow policy.  See bounds.admit for how ctx and wait are used.  The
+// caller must hold the write lock.
+func (s *Singly[T]) push(ctx context.Context, wait bool, data T, atHead bool) error {
+	ok, err := s.bounds.admit(ctx, wait, &s.size, func() {
+		if atHead {
+			s.popTail()
+		} else {
+			s.popHead()
+		}
+	})
+	if ok {
+		if atHead {
+			s.pushHead(data)
+		} else {
+			s.pushTail(data)
+		}
+	}
+	return err
+}
+
+// pushHead adds data to the front of the list.  The caller must hold the
+// write lock.
+func (s *Singly[T]) pushHead(data T) {
+	s.insertAfter(nil, data)
+}
+
+// pushTail adds data to the back of the list.  The caller must hold the
+// write lock.
+func (s *Singly[T]) pushTail(data T) {
+	s.insertAfter(s.tail, data)
+}
+
+// popHead removes data from the front of a non-empty list.  The caller must
+// hold the write lock.
+func (s *Singly[T]) popHead() T {
+	return s.removeAfter(nil)
+}
+
+// popTail removes data from the back of a non-empty list.  The caller must
+// hold the write lock.
+func (s *Singly[T]) popTail() T {
+	var pred *singlyNode[T]
+	if s.head != s.tail {
+		for pred = s.head; pred.Next != s.tail; pred = pred.Next {
+		}
+	}
+	return s.removeAfter(pred)
+}
+
+// insertAfter adds data to the list after pred, or at the front of the list
+// if pred is nil.  The caller must hold the write lock.
+func (s *Singly[T]) insertAfter(pred *singlyNode[T], data T) {
+	node := &singlyNode[T]{Data: data}
+	if pred == nil {
+		node.Next = s.head
+		s.head = node
+	} else {
+		node.Next = pred.Next
+		pred.Next = node
+	}
+	if pred == s.tail {
+		s.tail = node
+	}
+	s.size++
+	s.journal(singlyUndo[T]{kind: undoInsert, pred: pred})
+	s.notEmpty.Signal()
+}
+
+// removeAfter removes the node after pred, or the head of the list if pred
+// is nil, and returns its data.  The caller must hold the write lock.
+func (s *Singly[T]) removeAfter(pred *singlyNode[T]) T {
+	var node *singlyNode[T]
+	if pred == nil {
+		node = s.head
+		s.head = node.Next
+	} else {
+		node = pred.Next
+		pred.Next = node.Next
+	}
+	if node == s.tail {
+		s.tail = pred
+	}
+	node.Next = nil
+	s.size--
+	s.journal(singlyUndo[T]{kind: undoRemove, pred: pred, node: node})
+	s.bounds.removed(s.size)
+	return node.Data
+}
+
+// nodeAt returns the node at index i, which must be in range.  The caller
+// must hold the read or write lock.
+func (s *Singly[T]) nodeAt(i int) *singlyNode[T] {
+	tmp := s.head
+	for ; i > 0; i-- {
+		tmp = tmp.Next
+	}
+	return tmp
+}
+
+// checkIndex returns an IndexOutOfRangeError if i is not in [0, limit).  The
+// caller must hold the read or write lock.
+func (s *Singly[T]) checkIndex(i, limit int) error {
+	if i < 0 || i >= limit {
+		return IndexOutOfRangeError(fmt.Sprintf("index %d out of range for list of size %d", i, s.size))
+	}
+	return nil
+}
+
+// waitNotEmpty waits until the caller may take an item from the list, ctx is
+// done or the list is closed while empty.  Callers that are already waiting
+// go first.  Once the list is closed nothing more will be added, so the
+// remaining items go to whoever gets to them.  The caller must hold the
+// write lock.
+func (s *Singly[T]) waitNotEmpty(ctx context.Context) error {
+	var ticket uint64
+	for {
+		if s.bounds.closed {
+			if s.head == nil {
+				return s.emptyError()
 			}
-			s.size--
+			return nil
+		}
+		if s.notEmpty.Ready(ticket, s.size) {
+			return nil
+		}
+		if err := s.notEmpty.WaitTurn(ctx, &ticket); err != nil {
+			return err
+		}
+	}
+}
+
+// emptyError returns the error for popping from the list while it is empty.
+// The caller must hold the read or write lock.
+func (s *Singly[T]) emptyError() error {
+	if s.bounds.closed {
+		return DrainedListError("can't remove an item from a closed and empty list")
+	}
+	return EmptyListError("can't remove an item from an empty list")
+}
+
+// contains is Contains without locking.  The caller must hold the read or
+// write lock.
+func (s *Singly[T]) contains(comparison func(data T) (exists bool)) bool {
+	for tmp := s.head; tmp != nil; tmp = tmp.Next {
+		if comparison(tmp.Data) {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteMatches is Delete without locking.  The caller must hold the write
+// lock.
+func (s *Singly[T]) deleteMatches(numItems int, comparison func(data T) (shouldDelete bool)) (numDeleted int) {
+	var pred *singlyNode[T]
+	for tmp := s.head; tmp != nil; {
+		next := tmp.Next
+		if comparison(tmp.Data) {
+			s.removeAfter(pred)
 			numDeleted++
 			if numItems == numDeleted {
 				return
@@ -171,6 +650,53 @@ func (s *Singly) Delete(numItems int, comparison func(data interface{}) (shouldD
 		} else {
 			pred = tmp
 		}
+		tmp = next
 	}
 	return
 }
+
+// get is Get without locking.  The caller must hold the read or write lock.
+func (s *Singly[T]) get(i int) (data T, err error) {
+	if err = s.checkIndex(i, s.size); err != nil {
+		return
+	}
+	return s.nodeAt(i).Data, nil
+}
+
+// set is Set without locking.  The caller must hold the write lock.
+func (s *Singly[T]) set(i int, data T) error {
+	if err := s.checkIndex(i, s.size); err != nil {
+		return err
+	}
+	node := s.nodeAt(i)
+	s.journal(singlyUndo[T]{kind: undoSet, node: node, data: node.Data})
+	node.Data = data
+	return nil
+}
+
+// insertAt is InsertAt without locking.  The caller must hold the write lock.
+func (s *Singly[T]) insertAt(i int, data T) error {
+	if err := s.checkIndex(i, s.size+1); err != nil {
+		return err
+	}
+	if ok, err := s.bounds.admit(context.Background(), false, &s.size, nil); !ok {
+		return err
+	}
+	if i == 0 {
+		s.insertAfter(nil, data)
+	} else {
+		s.insertAfter(s.nodeAt(i-1), data)
+	}
+	return nil
+}
+
+// removeAt is RemoveAt without locking.  The caller must hold the write lock.
+func (s *Singly[T]) removeAt(i int) (data T, err error) {
+	if err = s.checkIndex(i, s.size); err != nil {
+		return
+	}
+	if i == 0 {
+		return s.removeAfter(nil), nil
+	}
+	return s.removeAfter(s.nodeAt(i - 1)), nil
+}
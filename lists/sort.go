@@ -0,0 +1,172 @@
+package lists
+
+import "context"
+
+// Sort sorts the list in place so that less never reports an item as being
+// less than the item before it.  Nodes are relinked rather than copied, so
+// sorting doesn't allocate.  Sort is a merge sort and so is stable too; it is
+// the same as SortStable.
+//
+// Runtime: O(n log n)
+func (s *Singly[T]) Sort(less func(a, b T) bool) {
+	s.SortStable(less)
+}
+
+// SortStable sorts the list in place like Sort, keeping items that are equal
+// according to less in their original order.
+//
+// Runtime: O(n log n)
+func (s *Singly[T]) SortStable(less func(a, b T) bool) {
+	s.rwLock.Lock()
+	defer s.rwLock.Unlock()
+	s.head, s.tail = mergeSort(s.head,
+		func(node *singlyNode[T]) **singlyNode[T] { return &node.Next },
+		func(a, b *singlyNode[T]) bool { return less(a.Data, b.Data) })
+}
+
+// IsSorted returns true if less never reports an item as being less than the
+// item before it.
+//
+// Runtime: O(n)
+func (s *Singly[T]) IsSorted(less func(a, b T) bool) bool {
+	s.rwLock.RLock()
+	defer s.rwLock.RUnlock()
+	for tmp := s.head; tmp != nil && tmp.Next != nil; tmp = tmp.Next {
+		if less(tmp.Next.Data, tmp.Data) {
+			return false
+		}
+	}
+	return true
+}
+
+// InsertSorted adds data to a list sorted by less so that it stays sorted.
+// data goes after any items equal to it, so inserting items one by one keeps
+// equal items in the order they were inserted.  On a bounded list that is
+// full, the Drop policy discards data and every other policy returns a
+// CapacityExceededError.  Returns a ClosedListError if the list has been
+// closed.
+//
+// Runtime: O(n)
+func (s *Singly[T]) InsertSorted(data T, less func(a, b T) bool) error {
+	s.rwLock.Lock()
+	defer s.rwLock.Unlock()
+	if ok, err := s.bounds.admit(context.Background(), false, &s.size, nil); !ok {
+		return err
+	}
+	var pred *singlyNode[T]
+	for tmp := s.head; tmp != nil && !less(data, tmp.Data); tmp = tmp.Next {
+		pred = tmp
+	}
+	s.insertAfter(pred, data)
+	return nil
+}
+
+// Sort sorts the list in place so that less never reports an item as being
+// less than the item before it.  Nodes are relinked rather than copied, so
+// sorting doesn't allocate and Elements stay valid.  Sort is a merge sort and
+// so is stable too; it is the same as SortStable.
+//
+// Runtime: O(n log n)
+func (d *Doubly[T]) Sort(less func(a, b T) bool) {
+	d.SortStable(less)
+}
+
+// SortStable sorts the list in place like Sort, keeping items that are equal
+// according to less in their original order.
+//
+// Runtime: O(n log n)
+func (d *Doubly[T]) SortStable(less func(a, b T) bool) {
+	d.rwLock.Lock()
+	defer d.rwLock.Unlock()
+	d.head, d.tail = mergeSort(d.head,
+		func(node *doublyNode[T]) **doublyNode[T] { return &node.Next },
+		func(a, b *doublyNode[T]) bool { return less(a.Data, b.Data) })
+	var prev *doublyNode[T]
+	for tmp := d.head; tmp != nil; tmp = tmp.Next {
+		tmp.Prev = prev
+		prev = tmp
+	}
+}
+
+// IsSorted returns true if less never reports an item as being less than the
+// item before it.
+//
+// Runtime: O(n)
+func (d *Doubly[T]) IsSorted(less func(a, b T) bool) bool {
+	d.rwLock.RLock()
+	defer d.rwLock.RUnlock()
+	for tmp := d.head; tmp != nil && tmp.Next != nil; tmp = tmp.Next {
+		if less(tmp.Next.Data, tmp.Data) {
+			return false
+		}
+	}
+	return true
+}
+
+// InsertSorted adds data to a list sorted by less so that it stays sorted.
+// data goes after any items equal to it, so inserting items one by one keeps
+// equal items in the order they were inserted.  On a bounded list that is
+// full, the Drop policy discards data and every other policy returns a
+// CapacityExceededError.  Returns a ClosedListError if the list has been
+// closed.
+//
+// Runtime: O(n), walking back from the tail, so inserting items that are
+// already nearly in order is fast
+func (d *Doubly[T]) InsertSorted(data T, less func(a, b T) bool) error {
+	d.rwLock.Lock()
+	defer d.rwLock.Unlock()
+	if ok, err := d.bounds.admit(context.Background(), false, &d.size, nil); !ok {
+		return err
+	}
+	pred := d.tail
+	for pred != nil && less(data, pred.Data) {
+		pred = pred.Prev
+	}
+	d.link(&doublyNode[T]{Data: data}, pred)
+	return nil
+}
+
+// mergeSort sorts the chain of nodes starting at head, following the pointer
+// returned by next, and returns its new head and tail.  It is a bottom-up
+// merge sort that merges runs of 1, 2, 4, ... nodes in place, so it needs no
+// extra memory and, since ties are taken from the earlier run, it is stable.
+func mergeSort[N any](head *N, next func(node *N) **N, less func(a, b *N) bool) (newHead, newTail *N) {
+	if head == nil {
+		return nil, nil
+	}
+	for k := 1; ; k *= 2 {
+		p := head
+		head, newTail = nil, nil
+		merges := 0
+		for p != nil {
+			merges++
+			// q starts the run after the k nodes starting at p
+			q, pSize := p, 0
+			for ; pSize < k && q != nil; pSize++ {
+				q = *next(q)
+			}
+			qSize := k
+			for pSize > 0 || (qSize > 0 && q != nil) {
+				var node *N
+				if pSize == 0 || (qSize > 0 && q != nil && less(q, p)) {
+					node, q = q, *next(q)
+					qSize--
+				} else {
+					node, p = p, *next(p)
+					pSize--
+				}
+				if newTail == nil {
+					head = node
+				} else {
+					*next(newTail) = node
+				}
+				newTail = node
+			}
+			p = q
+		}
+		*next(newTail) = nil
+		if merges <= 1 {
+			return head, newTail
+		}
+	}
+}
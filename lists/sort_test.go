@@ -0,0 +1,130 @@
+package lists
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func intLess(a, b int) bool {
+	return a < b
+}
+
+func TestSinglySort(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 7, 8, 100, 1000} {
+		values := rand.Perm(n)
+		s := singlyOf(values...)
+		s.Sort(intLess)
+		slices.Sort(values)
+		assert.Equal(t, values, slices.Collect(s.Values()), "n=%d", n)
+		assert.True(t, s.IsSorted(intLess))
+		assert.Equal(t, n, s.Size())
+
+		// the tail must be the last item
+		s.PushTail(n)
+		v, _ := s.PopTail()
+		assert.Equal(t, n, v)
+		v, _ = s.PopTail()
+		assert.Equal(t, n-1, v)
+	}
+}
+
+func TestDoublySort(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 7, 8, 100, 1000} {
+		values := rand.Perm(n)
+		d := doublyOf(values...)
+		d.Sort(intLess)
+		slices.Sort(values)
+		assert.Equal(t, values, slices.Collect(d.Values()), "n=%d", n)
+		reversed := slices.Clone(values)
+		slices.Reverse(reversed)
+		assert.Equal(t, reversed, backward(d), "n=%d", n)
+		assert.True(t, d.IsSorted(intLess))
+	}
+}
+
+func TestSortEmpty(t *testing.T) {
+	s := singlyOf()
+	s.Sort(intLess)
+	assert.True(t, s.IsEmpty())
+	d := doublyOf()
+	d.Sort(intLess)
+	assert.True(t, d.IsEmpty())
+	d.PushTail(1)
+	assert.Equal(t, []int{1}, backward(d))
+}
+
+func TestSortStable(t *testing.T) {
+	type pair struct{ key, order int }
+	byKey := func(a, b pair) bool { return a.key < b.key }
+	var values []pair
+	for i := 0; i < 200; i++ {
+		values = append(values, pair{rand.Intn(5), i})
+	}
+	expected := slices.Clone(values)
+	slices.SortStableFunc(expected, func(a, b pair) int { return a.key - b.key })
+
+	s := NewSinglyOf[pair]()
+	d := NewDoublyOf[pair]()
+	for _, v := range values {
+		s.PushTail(v)
+		d.PushTail(v)
+	}
+	s.SortStable(byKey)
+	d.SortStable(byKey)
+	assert.Equal(t, expected, slices.Collect(s.Values()))
+	assert.Equal(t, expected, slices.Collect(d.Values()))
+}
+
+func TestSortKeepsElements(t *testing.T) {
+	d := doublyOf(3, 1)
+	e, _ := d.PushTailElement(2)
+	d.Sort(intLess)
+	assert.NoError(t, d.MoveToHead(e))
+	assert.Equal(t, []int{2, 1, 3}, slices.Collect(d.Values()))
+}
+
+func TestIsSorted(t *testing.T) {
+	assert.True(t, singlyOf().IsSorted(intLess))
+	assert.True(t, singlyOf(1, 1, 2).IsSorted(intLess))
+	assert.False(t, singlyOf(1, 3, 2).IsSorted(intLess))
+	assert.True(t, doublyOf(1, 1, 2).IsSorted(intLess))
+	assert.False(t, doublyOf(2, 1).IsSorted(intLess))
+}
+
+func TestInsertSorted(t *testing.T) {
+	s := singlyOf()
+	d := doublyOf()
+	for _, v := range rand.Perm(50) {
+		assert.NoError(t, s.InsertSorted(v, intLess))
+		assert.NoError(t, d.InsertSorted(v, intLess))
+	}
+	assert.True(t, s.IsSorted(intLess))
+	assert.True(t, d.IsSorted(intLess))
+	assert.Equal(t, 50, s.Size())
+	v, _ := s.PopTail()
+	assert.Equal(t, 49, v)
+	assert.Equal(t, 50, len(backward(d)))
+
+	type pair struct{ key, order int }
+	byKey := func(a, b pair) bool { return a.key < b.key }
+	p := NewDoublyOf[pair]()
+	for i, key := range []int{2, 1, 2, 1} {
+		p.InsertSorted(pair{key, i}, byKey)
+	}
+	assert.Equal(t, []pair{{1, 1}, {1, 3}, {2, 0}, {2, 2}}, slices.Collect(p.Values()))
+}
+
+func TestInsertSortedBounded(t *testing.T) {
+	s := NewBoundedSingly[int](1, Evict)
+	assert.NoError(t, s.InsertSorted(1, intLess))
+	assert.IsType(t, CapacityExceededError(""), s.InsertSorted(0, intLess))
+	d := NewBoundedDoubly[int](1, Drop)
+	d.PushTail(1)
+	assert.NoError(t, d.InsertSorted(0, intLess))
+	assert.Equal(t, []int{1}, slices.Collect(d.Values()))
+	d.Close()
+	assert.IsType(t, ClosedListError(""), d.InsertSorted(0, intLess))
+}
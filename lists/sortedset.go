@@ -0,0 +1,164 @@
+package lists
+
+import (
+	"iter"
+	"sync/atomic"
+)
+
+// SortedSet goroutine-safe set of unique items kept in ascending order in a
+// lock-free singly-linked list, based on the Harris-Michael algorithm.  Add,
+// Remove and Contains never take a lock.
+//
+// Remove first marks the next pointer of the removed node, which logically
+// deletes it and stops any node being inserted after it, and then unlinks it.
+// Any goroutine that finds a marked node while searching finishes unlinking
+// it.
+type SortedSet[T any] struct {
+	// head is a sentinel node whose data is never compared
+	head    *setNode[T]
+	compare func(a, b T) int
+	size    atomic.Int64
+}
+
+type setNode[T any] struct {
+	next atomic.Pointer[markedRef[T]]
+	data T
+}
+
+// markedRef is an immutable next pointer paired with the deletion mark of the
+// node that holds it, so both can be changed with one compare-and-swap
+type markedRef[T any] struct {
+	node   *setNode[T]
+	marked bool
+}
+
+// NewSortedSet creates a new empty sorted set.  compare must return a
+// negative number if a sorts before b, a positive number if a sorts after b
+// and 0 if they are the same item, like cmp.Compare.
+func NewSortedSet[T any](compare func(a, b T) int) *SortedSet[T] {
+	s := &SortedSet[T]{head: &setNode[T]{}, compare: compare}
+	s.head.next.Store(&markedRef[T]{})
+	return s
+}
+
+// Len is the number of items in the set.  While other goroutines are adding
+// or removing items the result is only a snapshot.
+//
+// Runtime: O(1)
+func (s *SortedSet[T]) Len() int {
+	return int(s.size.Load())
+}
+
+// IsEmpty returns true if the set contains no items
+//
+// Runtime: O(1), plus any unlinking of removed nodes left behind by Remove
+func (s *SortedSet[T]) IsEmpty() bool {
+	_, _, curr := s.find(func(T) bool { return true })
+	return curr == nil
+}
+
+// Add inserts data into the set in order.  Returns false if the set already
+// contains an item that compares equal to data.
+//
+// Runtime: O(n)
+func (s *SortedSet[T]) Add(data T) bool {
+	node := &setNode[T]{data: data}
+	for {
+		pred, predRef, curr := s.find(s.atOrAfter(data))
+		if curr != nil && s.compare(curr.data, data) == 0 {
+			return false
+		}
+		node.next.Store(&markedRef[T]{node: curr})
+		if pred.next.CompareAndSwap(predRef, &markedRef[T]{node: node}) {
+			s.size.Add(1)
+			return true
+		}
+	}
+}
+
+// Remove deletes the item that compares equal to data from the set.  Returns
+// false if there is no such item.
+//
+// Runtime: O(n)
+func (s *SortedSet[T]) Remove(data T) bool {
+	for {
+		pred, predRef, curr := s.find(s.atOrAfter(data))
+		if curr == nil || s.compare(curr.data, data) != 0 {
+			return false
+		}
+		currRef := curr.next.Load()
+		if currRef.marked {
+			continue
+		}
+		if !curr.next.CompareAndSwap(currRef, &markedRef[T]{node: currRef.node, marked: true}) {
+			continue
+		}
+		s.size.Add(-1)
+		// curr is now logically deleted; if unlinking it fails the next find
+		// that passes it will finish the job
+		pred.next.CompareAndSwap(predRef, &markedRef[T]{node: currRef.node})
+		return true
+	}
+}
+
+// Contains returns true if the set contains an item that compares equal to
+// data.  It never writes to the list, so it is wait-free.
+//
+// Runtime: O(n)
+func (s *SortedSet[T]) Contains(data T) bool {
+	curr := s.head.next.Load().node
+	for curr != nil && s.compare(curr.data, data) < 0 {
+		curr = curr.next.Load().node
+	}
+	return curr != nil && s.compare(curr.data, data) == 0 && !curr.next.Load().marked
+}
+
+// All returns an iterator over the items in the set in ascending order.  It
+// takes no locks and is safe to use while other goroutines, or the loop body,
+// modify the set.  Every item yielded was in the set at some point during the
+// loop, each item is yielded at most once and items added or removed during
+// the loop may or may not be seen.
+//
+// Runtime: O(n)
+func (s *SortedSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for curr := s.head.next.Load().node; curr != nil; curr = curr.next.Load().node {
+			if !curr.next.Load().marked && !yield(curr.data) {
+				return
+			}
+		}
+	}
+}
+
+// atOrAfter returns a predicate for find that stops at the first item that
+// doesn't sort before data
+func (s *SortedSet[T]) atOrAfter(data T) func(T) bool {
+	return func(item T) bool { return s.compare(item, data) >= 0 }
+}
+
+// find returns the first unmarked node whose data satisfies stop, or nil if
+// there isn't one, along with its predecessor and the predecessor's next
+// pointer.  Marked nodes passed on the way are unlinked.
+func (s *SortedSet[T]) find(stop func(T) bool) (pred *setNode[T], predRef *markedRef[T], curr *setNode[T]) {
+retry:
+	pred = s.head
+	predRef = pred.next.Load()
+	curr = predRef.node
+	for curr != nil {
+		currRef := curr.next.Load()
+		if currRef.marked {
+			unlinked := &markedRef[T]{node: currRef.node}
+			if !pred.next.CompareAndSwap(predRef, unlinked) {
+				goto retry
+			}
+			predRef = unlinked
+			curr = currRef.node
+			continue
+		}
+		if stop(curr.data) {
+			return
+		}
+		pred, predRef, curr = curr, currRef, currRef.node
+	}
+	return
+}
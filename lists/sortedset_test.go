@@ -0,0 +1,133 @@
+package lists
+
+import (
+	"cmp"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SortedSetTestSuite struct {
+	suite.Suite
+	set *SortedSet[int]
+}
+
+func TestSortedSetTestSuite(t *testing.T) {
+	suite.Run(t, new(SortedSetTestSuite))
+}
+
+func (suite *SortedSetTestSuite) SetupTest() {
+	suite.set = NewSortedSet(cmp.Compare[int])
+}
+
+func (suite *SortedSetTestSuite) items() (items []int) {
+	for item := range suite.set.All() {
+		items = append(items, item)
+	}
+	return
+}
+
+func (suite *SortedSetTestSuite) TestEmpty() {
+	assert.Equal(suite.T(), 0, suite.set.Len())
+	assert.True(suite.T(), suite.set.IsEmpty())
+	assert.False(suite.T(), suite.set.Contains(1))
+	assert.False(suite.T(), suite.set.Remove(1))
+	assert.Nil(suite.T(), suite.items())
+}
+
+func (suite *SortedSetTestSuite) TestAdd() {
+	for _, item := range []int{5, 1, 9, 3, 7} {
+		assert.True(suite.T(), suite.set.Add(item), "Add(%d)", item)
+	}
+	assert.False(suite.T(), suite.set.Add(3), "duplicate items should not be added")
+	assert.Equal(suite.T(), 5, suite.set.Len())
+	assert.False(suite.T(), suite.set.IsEmpty())
+	assert.Equal(suite.T(), []int{1, 3, 5, 7, 9}, suite.items())
+	for _, item := range []int{1, 3, 5, 7, 9} {
+		assert.True(suite.T(), suite.set.Contains(item), "Contains(%d)", item)
+	}
+	for _, item := range []int{0, 2, 10} {
+		assert.False(suite.T(), suite.set.Contains(item), "Contains(%d)", item)
+	}
+}
+
+func (suite *SortedSetTestSuite) TestRemove() {
+	for i := 0; i < 6; i++ {
+		suite.set.Add(i)
+	}
+	assert.True(suite.T(), suite.set.Remove(0), "remove the first item")
+	assert.True(suite.T(), suite.set.Remove(5), "remove the last item")
+	assert.True(suite.T(), suite.set.Remove(3), "remove a middle item")
+	assert.False(suite.T(), suite.set.Remove(3), "items can only be removed once")
+	assert.False(suite.T(), suite.set.Contains(3))
+	assert.Equal(suite.T(), 3, suite.set.Len())
+	assert.Equal(suite.T(), []int{1, 2, 4}, suite.items())
+	assert.True(suite.T(), suite.set.Add(3), "removed items can be added again")
+	assert.Equal(suite.T(), []int{1, 2, 3, 4}, suite.items())
+}
+
+func (suite *SortedSetTestSuite) TestComparator() {
+	set := NewSortedSet(func(a, b string) int { return cmp.Compare(len(a), len(b)) })
+	set.Add("ccc")
+	set.Add("a")
+	assert.False(suite.T(), set.Add("b"), "items that compare equal are duplicates")
+	set.Add("bb")
+	var items []string
+	for item := range set.All() {
+		items = append(items, item)
+	}
+	assert.Equal(suite.T(), []string{"a", "bb", "ccc"}, items)
+}
+
+func (suite *SortedSetTestSuite) TestModifyWhileIterating() {
+	for i := 0; i < 10; i++ {
+		suite.set.Add(i * 2)
+	}
+	var items []int
+	for item := range suite.set.All() {
+		items = append(items, item)
+		suite.set.Remove(item + 4)
+		if item == 0 {
+			suite.set.Add(1)
+		}
+	}
+	assert.Equal(suite.T(), []int{0, 1, 2, 8, 10, 16, 18}, items, "items removed ahead of the loop should not be seen")
+}
+
+func (suite *SortedSetTestSuite) TestConcurrent() {
+	const goroutines, perGoroutine = 8, 300
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(2)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				suite.set.Add(i)
+				if i%3 == g%3 {
+					suite.set.Remove(i)
+				}
+			}
+		}(g)
+		go func() {
+			defer wg.Done()
+			prev := -1
+			for item := range suite.set.All() {
+				assert.True(suite.T(), item > prev, "All should yield ascending items")
+				prev = item
+			}
+		}()
+	}
+	wg.Wait()
+	items := suite.items()
+	assert.Len(suite.T(), items, suite.set.Len())
+	for i := 1; i < len(items); i++ {
+		assert.True(suite.T(), items[i] > items[i-1], "items should be unique and in order")
+	}
+	for i := 0; i < perGoroutine; i++ {
+		suite.set.Remove(i)
+	}
+	assert.True(suite.T(), suite.set.IsEmpty())
+	assert.Equal(suite.T(), 0, suite.set.Len())
+}
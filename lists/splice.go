@@ -0,0 +1,272 @@
+package lists
+
+// Concat moves every item of other to the back of the list, leaving other
+// empty.  Nodes are relinked rather than copied.  Returns a ClosedListError
+// if the list has been closed, a CapacityExceededError if a bounded list
+// doesn't have room for all of other's items, or an InvalidListError if
+// other is the list itself; nothing is moved in any of these cases.  The
+// OverflowPolicy doesn't apply to the items of other.
+//
+// Runtime: O(1)
+func (s *Singly[T]) Concat(other *Singly[T]) error {
+	if other == s {
+		return InvalidListError("can't concatenate a list onto itself")
+	}
+	first, second := lockPair[T](s, other)
+	defer unlockPair(first, second)
+	return s.spliceList(s.tail, other)
+}
+
+// SpliceAt moves every item of other into the list at index i, leaving other
+// empty, so that other's first item ends up at i.  i may equal Size() to add
+// the items to the back of the list.  Returns an IndexOutOfRangeError if i is
+// not in [0, Size()], and otherwise fails like Concat.
+//
+// Runtime: O(i)
+func (s *Singly[T]) SpliceAt(i int, other *Singly[T]) error {
+	if other == s {
+		return InvalidListError("can't splice a list into itself")
+	}
+	first, second := lockPair[T](s, other)
+	defer unlockPair(first, second)
+	if err := s.checkIndex(i, s.size+1); err != nil {
+		return err
+	}
+	var pred *singlyNode[T]
+	if i > 0 {
+		pred = s.nodeAt(i - 1)
+	}
+	return s.spliceList(pred, other)
+}
+
+// SplitAt removes the items from index i to the back of the list and returns
+// them as a new list.  i may equal Size(), which returns an empty list.  The
+// new list is created with the same capacity, OverflowPolicy and options as
+// this one; a list created WithLocker shares its lock with the new list.
+// Returns an IndexOutOfRangeError if i is not in [0, Size()].
+//
+// Runtime: O(i)
+func (s *Singly[T]) SplitAt(i int) (*Singly[T], error) {
+	s.rwLock.Lock()
+	defer s.rwLock.Unlock()
+	if err := s.checkIndex(i, s.size+1); err != nil {
+		return nil, err
+	}
+	var pred *singlyNode[T]
+	if i > 0 {
+		pred = s.nodeAt(i - 1)
+	}
+	return s.splitAfter(pred, i), nil
+}
+
+// SplitWhere removes the first item for which pred returns true, and every
+// item after it, and returns them as a new list created like SplitAt's.  The
+// new list is empty if pred doesn't return true for any item.
+//
+// Runtime: O(k), where k is the index of the first matching item
+func (s *Singly[T]) SplitWhere(pred func(data T) bool) *Singly[T] {
+	s.rwLock.Lock()
+	defer s.rwLock.Unlock()
+	var prev *singlyNode[T]
+	i := 0
+	for tmp := s.head; tmp != nil && !pred(tmp.Data); tmp = tmp.Next {
+		prev = tmp
+		i++
+	}
+	return s.splitAfter(prev, i)
+}
+
+// spliceList moves every node of other into the list after pred, or at the
+// front of the list if pred is nil.  The caller must hold the write locks of
+// both lists.
+func (s *Singly[T]) spliceList(pred *singlyNode[T], other *Singly[T]) error {
+	if s.bounds.closed {
+		return ClosedListError("can't add an item to a closed list")
+	}
+	if s.bounds.capacity > 0 && s.size+other.size > s.bounds.capacity {
+		return CapacityExceededError("can't add the items of another list to a full list")
+	}
+	if other.head == nil {
+		return nil
+	}
+	if pred == nil {
+		other.tail.Next = s.head
+		s.head = other.head
+	} else {
+		other.tail.Next = pred.Next
+		pred.Next = other.head
+	}
+	if s.tail == pred {
+		s.tail = other.tail
+	}
+	s.size += other.size
+	s.notEmpty.SignalN(other.size)
+	other.head, other.tail, other.size = nil, nil, 0
+	other.bounds.removedMany(0)
+	return nil
+}
+
+// splitAfter moves the nodes after pred, or every node if pred is nil, to a
+// new list.  i is the index of the first node moved.  The caller must hold the
+// write lock.
+func (s *Singly[T]) splitAfter(pred *singlyNode[T], i int) *Singly[T] {
+	rest := newSingly[T](bounds{capacity: s.bounds.capacity, policy: s.bounds.policy}, s.opts)
+	first := s.head
+	if pred != nil {
+		first = pred.Next
+	}
+	if first == nil {
+		return rest
+	}
+	rest.head, rest.tail, rest.size = first, s.tail, s.size-i
+	if pred == nil {
+		s.head = nil
+	} else {
+		pred.Next = nil
+	}
+	s.tail = pred
+	s.size = i
+	s.bounds.removedMany(s.size)
+	return rest
+}
+
+// Concat moves every item of other to the back of the list, leaving other
+// empty.  Nodes are relinked rather than copied, and Elements of other's
+// items stay valid as Elements of this list.  Returns a ClosedListError if
+// the list has been closed, a CapacityExceededError if a bounded list doesn't
+// have room for all of other's items, or an InvalidListError if other is the
+// list itself; nothing is moved in any of these cases.  The OverflowPolicy
+// doesn't apply to the items of other.
+//
+// Runtime: O(1)
+func (d *Doubly[T]) Concat(other *Doubly[T]) error {
+	if other == d {
+		return InvalidListError("can't concatenate a list onto itself")
+	}
+	first, second := lockPair[T](d, other)
+	defer unlockPair(first, second)
+	return d.spliceList(d.tail, other)
+}
+
+// SpliceAt moves every item of other into the list at index i, leaving other
+// empty, so that other's first item ends up at i.  i may equal Size() to add
+// the items to the back of the list.  Returns an IndexOutOfRangeError if i is
+// not in [0, Size()], and otherwise fails like Concat.
+//
+// Runtime: O(n), walking from whichever end of the list is closer to i
+func (d *Doubly[T]) SpliceAt(i int, other *Doubly[T]) error {
+	if other == d {
+		return InvalidListError("can't splice a list into itself")
+	}
+	first, second := lockPair[T](d, other)
+	defer unlockPair(first, second)
+	if err := d.checkIndex(i, d.size+1); err != nil {
+		return err
+	}
+	var pred *doublyNode[T]
+	if i == d.size {
+		pred = d.tail
+	} else {
+		pred = d.nodeAt(i).Prev
+	}
+	return d.spliceList(pred, other)
+}
+
+// SplitAt removes the items from index i to the back of the list and returns
+// them as a new list.  i may equal Size(), which returns an empty list.
+// Elements of the moved items stay valid as Elements of the new list.  The
+// new list is created with the same capacity, OverflowPolicy and options as
+// this one; a list created WithLocker shares its lock with the new list.
+// Returns an IndexOutOfRangeError if i is not in [0, Size()].
+//
+// Runtime: O(n-i) to hand the moved items to the new list, plus walking from
+// whichever end of the list is closer to i
+func (d *Doubly[T]) SplitAt(i int) (*Doubly[T], error) {
+	d.rwLock.Lock()
+	defer d.rwLock.Unlock()
+	if err := d.checkIndex(i, d.size+1); err != nil {
+		return nil, err
+	}
+	var first *doublyNode[T]
+	if i < d.size {
+		first = d.nodeAt(i)
+	}
+	return d.splitFrom(first, i), nil
+}
+
+// SplitWhere removes the first item for which pred returns true, and every
+// item after it, and returns them as a new list created like SplitAt's.  The
+// new list is empty if pred doesn't return true for any item.
+//
+// Runtime: O(n)
+func (d *Doubly[T]) SplitWhere(pred func(data T) bool) *Doubly[T] {
+	d.rwLock.Lock()
+	defer d.rwLock.Unlock()
+	tmp := d.head
+	i := 0
+	for ; tmp != nil && !pred(tmp.Data); tmp = tmp.Next {
+		i++
+	}
+	return d.splitFrom(tmp, i)
+}
+
+// spliceList moves every node of other into the list after pred, or at the
+// front of the list if pred is nil.  Instead of updating each node, other's
+// owner is forwarded to this list's and other gets a new one.  The caller
+// must hold the write locks of both lists.
+func (d *Doubly[T]) spliceList(pred *doublyNode[T], other *Doubly[T]) error {
+	if d.bounds.closed {
+		return ClosedListError("can't add an item to a closed list")
+	}
+	if d.bounds.capacity > 0 && d.size+other.size > d.bounds.capacity {
+		return CapacityExceededError("can't add the items of another list to a full list")
+	}
+	if other.head == nil {
+		return nil
+	}
+	var succ *doublyNode[T]
+	if pred == nil {
+		succ = d.head
+		d.head = other.head
+	} else {
+		succ = pred.Next
+		pred.Next = other.head
+	}
+	if succ == nil {
+		d.tail = other.tail
+	} else {
+		succ.Prev = other.tail
+	}
+	other.head.Prev, other.tail.Next = pred, succ
+	d.size += other.size
+	d.notEmpty.SignalN(other.size)
+	other.owner.next.Store(d.owner)
+	other.owner = &owner[T]{list: other}
+	other.head, other.tail, other.size = nil, nil, 0
+	other.bounds.removedMany(0)
+	return nil
+}
+
+// splitFrom moves first, which is at index i, and every node after it to a
+// new list.  first may be nil to return an empty list.  The caller must hold
+// the write lock.
+func (d *Doubly[T]) splitFrom(first *doublyNode[T], i int) *Doubly[T] {
+	rest := newDoubly[T](bounds{capacity: d.bounds.capacity, policy: d.bounds.policy}, d.opts)
+	if first == nil {
+		return rest
+	}
+	for tmp := first; tmp != nil; tmp = tmp.Next {
+		tmp.owner.Store(rest.owner)
+	}
+	rest.head, rest.tail, rest.size = first, d.tail, d.size-i
+	if first.Prev == nil {
+		d.head = nil
+	} else {
+		first.Prev.Next = nil
+	}
+	d.tail = first.Prev
+	first.Prev = nil
+	d.size = i
+	d.bounds.removedMany(d.size)
+	return rest
+}
@@ -0,0 +1,246 @@
+package lists
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func singlyOf(values ...int) *Singly[int] {
+	s := NewSinglyOf[int]()
+	for _, v := range values {
+		s.PushTail(v)
+	}
+	return s
+}
+
+func doublyOf(values ...int) *Doubly[int] {
+	d := NewDoublyOf[int]()
+	for _, v := range values {
+		d.PushTail(v)
+	}
+	return d
+}
+
+// backward returns the items of d from tail to head, to check the Prev links
+func backward(d *Doubly[int]) (items []int) {
+	for _, item := range d.Backward() {
+		items = append(items, item)
+	}
+	return
+}
+
+func TestSinglyConcat(t *testing.T) {
+	s, other := singlyOf(1, 2), singlyOf(3, 4)
+	assert.NoError(t, s.Concat(other))
+	assert.Equal(t, []int{1, 2, 3, 4}, slices.Collect(s.Values()))
+	assert.Equal(t, 4, s.Size())
+	assert.True(t, other.IsEmpty())
+	assert.Equal(t, 0, other.Size())
+
+	// the tail must have moved so that pushes and pops still work
+	s.PushTail(5)
+	v, _ := s.PopTail()
+	assert.Equal(t, 5, v)
+	v, _ = s.PopTail()
+	assert.Equal(t, 4, v)
+
+	empty := singlyOf()
+	assert.NoError(t, empty.Concat(singlyOf(1)))
+	assert.NoError(t, empty.Concat(singlyOf()))
+	assert.Equal(t, []int{1}, slices.Collect(empty.Values()))
+	assert.IsType(t, InvalidListError(""), empty.Concat(empty))
+}
+
+func TestSinglySpliceAt(t *testing.T) {
+	s := singlyOf(1, 4)
+	assert.NoError(t, s.SpliceAt(1, singlyOf(2, 3)))
+	assert.NoError(t, s.SpliceAt(0, singlyOf(0)))
+	assert.NoError(t, s.SpliceAt(5, singlyOf(5)))
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5}, slices.Collect(s.Values()))
+	assert.Equal(t, 6, s.Size())
+	v, _ := s.PopTail()
+	assert.Equal(t, 5, v)
+
+	other := singlyOf(9)
+	assert.IsType(t, IndexOutOfRangeError(""), s.SpliceAt(7, other))
+	assert.Equal(t, 1, other.Size(), "nothing should move on error")
+}
+
+func TestSinglySplitAt(t *testing.T) {
+	s := singlyOf(1, 2, 3, 4)
+	rest, err := s.SplitAt(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, slices.Collect(s.Values()))
+	assert.Equal(t, []int{2, 3, 4}, slices.Collect(rest.Values()))
+	assert.Equal(t, 1, s.Size())
+	assert.Equal(t, 3, rest.Size())
+	s.PushTail(5)
+	rest.PushTail(6)
+	assert.Equal(t, []int{1, 5}, slices.Collect(s.Values()))
+	assert.Equal(t, []int{2, 3, 4, 6}, slices.Collect(rest.Values()))
+
+	rest, err = s.SplitAt(2)
+	assert.NoError(t, err)
+	assert.True(t, rest.IsEmpty())
+	rest, err = s.SplitAt(0)
+	assert.NoError(t, err)
+	assert.True(t, s.IsEmpty())
+	assert.Equal(t, []int{1, 5}, slices.Collect(rest.Values()))
+
+	_, err = s.SplitAt(1)
+	assert.IsType(t, IndexOutOfRangeError(""), err)
+}
+
+func TestSinglySplitWhere(t *testing.T) {
+	s := singlyOf(1, 2, 3, 4)
+	rest := s.SplitWhere(func(data int) bool { return data > 2 })
+	assert.Equal(t, []int{1, 2}, slices.Collect(s.Values()))
+	assert.Equal(t, []int{3, 4}, slices.Collect(rest.Values()))
+	rest = s.SplitWhere(func(data int) bool { return data > 10 })
+	assert.True(t, rest.IsEmpty())
+	assert.Equal(t, 2, s.Size())
+}
+
+func TestDoublyConcat(t *testing.T) {
+	d, other := doublyOf(1, 2), NewDoublyOf[int]()
+	e, _ := other.PushTailElement(3)
+	other.PushTail(4)
+	assert.NoError(t, d.Concat(other))
+	assert.Equal(t, []int{1, 2, 3, 4}, slices.Collect(d.Values()))
+	assert.Equal(t, []int{4, 3, 2, 1}, backward(d))
+	assert.Equal(t, 4, d.Size())
+	assert.True(t, other.IsEmpty())
+
+	// the Element moved with its item
+	_, err := other.Remove(e)
+	assert.IsType(t, InvalidElementError(""), err)
+	assert.NoError(t, d.MoveToHead(e))
+	assert.Equal(t, []int{3, 1, 2, 4}, slices.Collect(d.Values()))
+
+	// and keeps following it through further concatenations
+	third := doublyOf(0)
+	assert.NoError(t, third.Concat(d))
+	v, err := third.Remove(e)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, v)
+	assert.Equal(t, []int{0, 1, 2, 4}, slices.Collect(third.Values()))
+
+	// other is still usable, with Elements of its own
+	e, _ = other.PushTailElement(5)
+	_, err = other.Value(e)
+	assert.NoError(t, err)
+	assert.IsType(t, InvalidListError(""), other.Concat(other))
+}
+
+func TestDoublySpliceAt(t *testing.T) {
+	d := doublyOf(1, 4)
+	assert.NoError(t, d.SpliceAt(1, doublyOf(2, 3)))
+	assert.NoError(t, d.SpliceAt(0, doublyOf(0)))
+	assert.NoError(t, d.SpliceAt(5, doublyOf(5)))
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5}, slices.Collect(d.Values()))
+	assert.Equal(t, []int{5, 4, 3, 2, 1, 0}, backward(d))
+	assert.IsType(t, IndexOutOfRangeError(""), d.SpliceAt(-1, doublyOf(9)))
+}
+
+func TestDoublySplitAt(t *testing.T) {
+	d := NewDoublyOf[int]()
+	d.PushTail(1)
+	e, _ := d.PushTailElement(2)
+	d.PushTail(3)
+	rest, err := d.SplitAt(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, slices.Collect(d.Values()))
+	assert.Equal(t, []int{1}, backward(d))
+	assert.Equal(t, []int{2, 3}, slices.Collect(rest.Values()))
+	assert.Equal(t, []int{3, 2}, backward(rest))
+	assert.Equal(t, 1, d.Size())
+	assert.Equal(t, 2, rest.Size())
+
+	_, err = d.Remove(e)
+	assert.IsType(t, InvalidElementError(""), err)
+	assert.NoError(t, rest.MoveToTail(e))
+	assert.Equal(t, []int{3, 2}, slices.Collect(rest.Values()))
+
+	rest, err = d.SplitAt(0)
+	assert.NoError(t, err)
+	assert.True(t, d.IsEmpty())
+	assert.Equal(t, []int{1}, slices.Collect(rest.Values()))
+	_, err = d.SplitAt(1)
+	assert.IsType(t, IndexOutOfRangeError(""), err)
+}
+
+func TestDoublySplitWhere(t *testing.T) {
+	d := doublyOf(1, 2, 3, 4)
+	rest := d.SplitWhere(func(data int) bool { return data%2 == 0 })
+	assert.Equal(t, []int{1}, slices.Collect(d.Values()))
+	assert.Equal(t, []int{2, 3, 4}, slices.Collect(rest.Values()))
+	rest = d.SplitWhere(func(data int) bool { return false })
+	assert.True(t, rest.IsEmpty())
+}
+
+func TestSplitKeepsBoundsAndOptions(t *testing.T) {
+	d := NewBoundedDoubly[int](3, Reject, WithoutLocking())
+	d.PushTail(1)
+	d.PushTail(2)
+	rest, _ := d.SplitAt(0)
+	assert.Equal(t, 3, rest.Capacity())
+	assert.Equal(t, noLocker{}, rest.rwLock)
+
+	var mu sync.Mutex
+	s := NewSinglyOf[int](WithLocker(&mu))
+	other, _ := s.SplitAt(0)
+	assert.True(t, s.rwLock == other.rwLock, "a lock given with WithLocker should be shared")
+	assert.False(t, singlyOf().rwLock == singlyOf().rwLock)
+}
+
+func TestConcatCapacityAndClose(t *testing.T) {
+	d := NewBoundedDoubly[int](3, Evict)
+	d.PushTail(1)
+	d.PushTail(2)
+	other := doublyOf(3, 4)
+	assert.IsType(t, CapacityExceededError(""), d.Concat(other))
+	assert.Equal(t, 2, other.Size(), "nothing should move on error")
+	other.PopTail()
+	assert.NoError(t, d.Concat(other))
+	assert.Equal(t, []int{1, 2, 3}, slices.Collect(d.Values()))
+
+	// concatenating from a closed list drains it
+	src := singlyOf(5)
+	src.Close()
+	dst := singlyOf()
+	assert.NoError(t, dst.Concat(src))
+	select {
+	case <-src.Done():
+	default:
+		t.Fatal("Done should be closed once a closed list is drained")
+	}
+	assert.IsType(t, ClosedListError(""), src.Concat(singlyOf(6)))
+}
+
+func TestConcatConcurrentOppositeDirections(t *testing.T) {
+	a, b := doublyOf(1, 2, 3), doublyOf(4, 5, 6)
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				a.Concat(b)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				b.SpliceAt(0, a)
+			}
+		}()
+	}
+	wg.Wait()
+	items := append(slices.Collect(a.Values()), slices.Collect(b.Values())...)
+	slices.Sort(items)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6}, items)
+	assert.Equal(t, 6, a.Size()+b.Size())
+}
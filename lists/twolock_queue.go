@@ -0,0 +1,85 @@
+package lists
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// TwoLockQueue goroutine-safe FIFO queue with separate locks for its head
+// and tail, based on the Michael-Scott two-lock algorithm.  The head is a
+// sentinel node, so producers in PushTail and consumers in PopHead never
+// touch the same node's fields under different locks and don't wait on each
+// other.  It implements Queue.
+type TwoLockQueue[T any] struct {
+	headLock sync.Mutex
+	// head is a sentinel node whose next node holds the front of the queue
+	head     *queueNode[T]
+	tailLock sync.Mutex
+	tail     *queueNode[T]
+	size     atomic.Int64
+}
+
+var _ Queue[interface{}] = (*TwoLockQueue[interface{}])(nil)
+
+// NewTwoLockQueue creates a new empty two-lock queue holding items of type T
+func NewTwoLockQueue[T any]() *TwoLockQueue[T] {
+	sentinel := &queueNode[T]{}
+	return &TwoLockQueue[T]{head: sentinel, tail: sentinel}
+}
+
+// Size of the queue.  It counts an item once PushTail has linked it, so it
+// never includes items that PopHead can't remove yet, but while pushes are in
+// progress it can briefly leave out items that PopHead can already remove.
+// It is exact whenever no push is in progress.
+//
+// Runtime: O(1)
+func (q *TwoLockQueue[T]) Size() int {
+	// a PopHead can take an item before the PushTail that linked it has
+	// counted it, briefly taking the count below zero
+	return max(int(q.size.Load()), 0)
+}
+
+// IsEmpty returns true if the queue contains no items
+//
+// Runtime: O(1)
+func (q *TwoLockQueue[T]) IsEmpty() bool {
+	q.headLock.Lock()
+	defer q.headLock.Unlock()
+	return q.head.next.Load() == nil
+}
+
+// PushTail adds data to the back of the queue.  Only the tail lock is held.
+// The queue is unbounded, so it always returns nil.
+//
+// Runtime: O(1)
+func (q *TwoLockQueue[T]) PushTail(data T) error {
+	node := &queueNode[T]{data: data}
+	q.tailLock.Lock()
+	defer q.tailLock.Unlock()
+	// next is atomic because PopHead reads the sentinel's next pointer under
+	// the head lock while the queue is empty and head and tail are the same
+	// node
+	q.tail.next.Store(node)
+	q.tail = node
+	q.size.Add(1)
+	return nil
+}
+
+// PopHead removes data from the front of the queue.  Returns an
+// EmptyListError if there are no items in the queue.  Only the head lock is
+// held.
+//
+// Runtime: O(1)
+func (q *TwoLockQueue[T]) PopHead() (data T, err error) {
+	q.headLock.Lock()
+	defer q.headLock.Unlock()
+	next := q.head.next.Load()
+	if next == nil {
+		return data, EmptyListError("can't remove an item from an empty list")
+	}
+	// next becomes the new sentinel, so clear its data to let it be freed
+	data, next.data = next.data, data
+	q.head = next
+	q.size.Add(-1)
+	return data, nil
+}
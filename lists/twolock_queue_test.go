@@ -0,0 +1,45 @@
+package lists
+
+import (
+	"sync"
+	"testing"
+)
+
+// benchmarkProducersConsumers runs equal numbers of goroutines that only
+// push and only pop, which is where separate head and tail locks help
+func benchmarkProducersConsumers(b *testing.B, queue Queue[int]) {
+	const goroutines = 4
+	var wg sync.WaitGroup
+	perGoroutine := b.N / goroutines
+	b.ResetTimer()
+	for g := 0; g < goroutines; g++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				queue.PushTail(i)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; {
+				if _, err := queue.PopHead(); err == nil {
+					i++
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkTwoLockQueueProducersConsumers(b *testing.B) {
+	benchmarkProducersConsumers(b, NewTwoLockQueue[int]())
+}
+
+func BenchmarkDoublyQueueProducersConsumers(b *testing.B) {
+	benchmarkProducersConsumers(b, NewDoublyOf[int]())
+}
+
+func BenchmarkLockFreeQueueProducersConsumers(b *testing.B) {
+	benchmarkProducersConsumers(b, NewLockFreeQueue[int]())
+}
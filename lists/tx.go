@@ -0,0 +1,396 @@
+package lists
+
+import (
+	"context"
+	"iter"
+)
+
+// ListTx is the view of a Singly or Doubly passed to the callback of Do.  Its
+// methods behave like the list methods of the same name, but run under the
+// write lock already held by Do, so a sequence of them is atomic.  Pushes
+// never wait: they return a CapacityExceededError where the list method
+// would block and a ClosedListError if the list has been closed.  A ListTx
+// must not be used after the callback returns.
+type ListTx[T any] interface {
+	Size() int
+	IsEmpty() bool
+	PushHead(data T) error
+	PushTail(data T) error
+	PopHead() (data T, err error)
+	PopTail() (data T, err error)
+	PeekHead() (data T, err error)
+	PeekTail() (data T, err error)
+	Contains(comparison func(data T) (exists bool)) bool
+	Delete(numItems int, comparison func(data T) (shouldDelete bool)) (numDeleted int)
+	Get(i int) (data T, err error)
+	Set(i int, data T) error
+	InsertAt(i int, data T) error
+	RemoveAt(i int) (data T, err error)
+	All() iter.Seq2[int, T]
+}
+
+// Do runs fn under the write lock with a ListTx that can read and modify the
+// list, so that a sequence of operations, like checking the size and then
+// popping twice, can't be interleaved with other goroutines.  If fn returns
+// an error or panics, every change it made is rolled back and the list is
+// left exactly as it was, including which Elements are valid and its
+// OverflowStats.  Returns the error from fn.
+//
+// fn must not call methods on the list itself, only on the ListTx, or it will
+// deadlock.
+//
+// Runtime: O(1) on top of fn, plus O(k) to roll back k changes
+func (d *Doubly[T]) Do(fn func(tx ListTx[T]) error) (err error) {
+	d.rwLock.Lock()
+	defer d.rwLock.Unlock()
+	tx := &doublyTx[T]{d: d, stats: d.bounds.stats}
+	d.tx = tx
+	d.bounds.holdDone = true
+	committed := false
+	defer func() {
+		d.tx, tx.d = nil, nil
+		if !committed {
+			d.rollback(tx)
+		}
+		d.bounds.holdDone = false
+		d.bounds.checkDone(d.size)
+	}()
+	if err = fn(tx); err == nil {
+		committed = true
+	}
+	return
+}
+
+// doublyUndo is an entry in the undo log of a transaction on a Doubly
+type doublyUndo[T any] struct {
+	kind undoKind
+	node *doublyNode[T]
+	// pred is the node that was before a removed node, or nil if it was the
+	// head
+	pred *doublyNode[T]
+	// data is the data replaced by Set
+	data T
+}
+
+// journal records u in the undo log of the running transaction, if any.  The
+// caller must hold the write lock.
+func (d *Doubly[T]) journal(u doublyUndo[T]) {
+	if d.tx != nil {
+		d.tx.undo = append(d.tx.undo, u)
+	}
+}
+
+// rollback undoes every change recorded by tx, newest first.  The caller must
+// hold the write lock.
+func (d *Doubly[T]) rollback(tx *doublyTx[T]) {
+	d.bounds.stats = tx.stats
+	if len(tx.undo) == 0 {
+		return
+	}
+	for i := len(tx.undo) - 1; i >= 0; i-- {
+		u := tx.undo[i]
+		switch u.kind {
+		case undoInsert:
+			d.unsplice(u.node)
+			u.node.owner.Store(nil)
+			d.size--
+		case undoRemove:
+			d.splice(u.node, u.pred)
+			u.node.owner.Store(d.owner)
+			d.size++
+		case undoSet:
+			u.node.Data = u.data
+		}
+	}
+	// waiters may have been signaled for changes that no longer exist, so
+	// wake everyone to check again
+	d.notEmpty.Broadcast()
+	d.bounds.notFull.Broadcast()
+}
+
+// Do runs fn under the write lock with a ListTx that can read and modify the
+// list, so that a sequence of operations, like checking the size and then
+// popping twice, can't be interleaved with other goroutines.  If fn returns
+// an error or panics, every change it made is rolled back and the list is
+// left exactly as it was, including its OverflowStats.  Returns the error
+// from fn.
+//
+// fn must not call methods on the list itself, only on the ListTx, or it will
+// deadlock.
+//
+// Runtime: O(1) on top of fn, plus O(k) to roll back k changes
+func (s *Singly[T]) Do(fn func(tx ListTx[T]) error) (err error) {
+	s.rwLock.Lock()
+	defer s.rwLock.Unlock()
+	tx := &singlyTx[T]{s: s, stats: s.bounds.stats}
+	s.tx = tx
+	s.bounds.holdDone = true
+	committed := false
+	defer func() {
+		s.tx, tx.s = nil, nil
+		if !committed {
+			s.rollback(tx)
+		}
+		s.bounds.holdDone = false
+		s.bounds.checkDone(s.size)
+	}()
+	if err = fn(tx); err == nil {
+		committed = true
+	}
+	return
+}
+
+// undoKind is the kind of change an undo log entry reverses
+type undoKind int
+
+const (
+	// undoInsert reverses adding a node
+	undoInsert undoKind = iota
+	// undoRemove reverses removing a node
+	undoRemove
+	// undoSet reverses replacing the data of a node
+	undoSet
+)
+
+// singlyUndo is an entry in the undo log of a transaction on a Singly
+type singlyUndo[T any] struct {
+	kind undoKind
+	// pred is the node before the one added or removed, or nil if that node
+	// was the head
+	pred *singlyNode[T]
+	// node is the node removed, or whose data was replaced
+	node *singlyNode[T]
+	// data is the data replaced by Set
+	data T
+}
+
+// journal records u in the undo log of the running transaction, if any.  The
+// caller must hold the write lock.
+func (s *Singly[T]) journal(u singlyUndo[T]) {
+	if s.tx != nil {
+		s.tx.undo = append(s.tx.undo, u)
+	}
+}
+
+// rollback undoes every change recorded by tx, newest first.  The caller must
+// hold the write lock.
+func (s *Singly[T]) rollback(tx *singlyTx[T]) {
+	s.bounds.stats = tx.stats
+	if len(tx.undo) == 0 {
+		return
+	}
+	for i := len(tx.undo) - 1; i >= 0; i-- {
+		u := tx.undo[i]
+		switch u.kind {
+		case undoInsert:
+			var node *singlyNode[T]
+			if u.pred == nil {
+				node = s.head
+				s.head = node.Next
+			} else {
+				node = u.pred.Next
+				u.pred.Next = node.Next
+			}
+			if node == s.tail {
+				s.tail = u.pred
+			}
+			s.size--
+		case undoRemove:
+			if u.pred == nil {
+				u.node.Next = s.head
+				s.head = u.node
+			} else {
+				u.node.Next = u.pred.Next
+				u.pred.Next = u.node
+			}
+			if u.pred == s.tail {
+				s.tail = u.node
+			}
+			s.size++
+		case undoSet:
+			u.node.Data = u.data
+		}
+	}
+	// waiters may have been signaled for changes that no longer exist, so
+	// wake everyone to check again
+	s.notEmpty.Broadcast()
+	s.bounds.notFull.Broadcast()
+}
+
+type singlyTx[T any] struct {
+	s *Singly[T]
+	// undo is the log of changes to roll back if the transaction fails
+	undo []singlyUndo[T]
+	// stats are the list's OverflowStats when the transaction started
+	stats OverflowStats
+}
+
+func (tx *singlyTx[T]) Size() int {
+	return tx.s.size
+}
+
+func (tx *singlyTx[T]) IsEmpty() bool {
+	return tx.s.head == nil
+}
+
+func (tx *singlyTx[T]) PushHead(data T) error {
+	return tx.s.push(context.Background(), false, data, true)
+}
+
+func (tx *singlyTx[T]) PushTail(data T) error {
+	return tx.s.push(context.Background(), false, data, false)
+}
+
+func (tx *singlyTx[T]) PopHead() (data T, err error) {
+	if tx.s.head == nil {
+		return data, tx.s.emptyError()
+	}
+	return tx.s.popHead(), nil
+}
+
+func (tx *singlyTx[T]) PopTail() (data T, err error) {
+	if tx.s.head == nil {
+		return data, tx.s.emptyError()
+	}
+	return tx.s.popTail(), nil
+}
+
+func (tx *singlyTx[T]) PeekHead() (data T, err error) {
+	if tx.s.head == nil {
+		return data, EmptyListError("can't peek at an empty list")
+	}
+	return tx.s.head.Data, nil
+}
+
+func (tx *singlyTx[T]) PeekTail() (data T, err error) {
+	if tx.s.tail == nil {
+		return data, EmptyListError("can't peek at an empty list")
+	}
+	return tx.s.tail.Data, nil
+}
+
+func (tx *singlyTx[T]) Contains(comparison func(data T) (exists bool)) bool {
+	return tx.s.contains(comparison)
+}
+
+func (tx *singlyTx[T]) Delete(numItems int, comparison func(data T) (shouldDelete bool)) (numDeleted int) {
+	return tx.s.deleteMatches(numItems, comparison)
+}
+
+func (tx *singlyTx[T]) Get(i int) (data T, err error) {
+	return tx.s.get(i)
+}
+
+func (tx *singlyTx[T]) Set(i int, data T) error {
+	return tx.s.set(i, data)
+}
+
+func (tx *singlyTx[T]) InsertAt(i int, data T) error {
+	return tx.s.insertAt(i, data)
+}
+
+func (tx *singlyTx[T]) RemoveAt(i int) (data T, err error) {
+	return tx.s.removeAt(i)
+}
+
+func (tx *singlyTx[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for tmp := tx.s.head; tmp != nil; tmp = tmp.Next {
+			if !yield(i, tmp.Data) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+type doublyTx[T any] struct {
+	d *Doubly[T]
+	// undo is the log of changes to roll back if the transaction fails
+	undo []doublyUndo[T]
+	// stats are the list's OverflowStats when the transaction started
+	stats OverflowStats
+}
+
+func (tx *doublyTx[T]) Size() int {
+	return tx.d.size
+}
+
+func (tx *doublyTx[T]) IsEmpty() bool {
+	return tx.d.head == nil
+}
+
+func (tx *doublyTx[T]) PushHead(data T) error {
+	_, err := tx.d.push(context.Background(), false, data, true)
+	return err
+}
+
+func (tx *doublyTx[T]) PushTail(data T) error {
+	_, err := tx.d.push(context.Background(), false, data, false)
+	return err
+}
+
+func (tx *doublyTx[T]) PopHead() (data T, err error) {
+	if tx.d.head == nil {
+		return data, tx.d.emptyError()
+	}
+	return tx.d.popHead(), nil
+}
+
+func (tx *doublyTx[T]) PopTail() (data T, err error) {
+	if tx.d.head == nil {
+		return data, tx.d.emptyError()
+	}
+	return tx.d.popTail(), nil
+}
+
+func (tx *doublyTx[T]) PeekHead() (data T, err error) {
+	if tx.d.head == nil {
+		return data, EmptyListError("can't peek at an empty list")
+	}
+	return tx.d.head.Data, nil
+}
+
+func (tx *doublyTx[T]) PeekTail() (data T, err error) {
+	if tx.d.tail == nil {
+		return data, EmptyListError("can't peek at an empty list")
+	}
+	return tx.d.tail.Data, nil
+}
+
+func (tx *doublyTx[T]) Contains(comparison func(data T) (exists bool)) bool {
+	return tx.d.contains(comparison)
+}
+
+func (tx *doublyTx[T]) Delete(numItems int, comparison func(data T) (shouldDelete bool)) (numDeleted int) {
+	return tx.d.deleteMatches(numItems, comparison)
+}
+
+func (tx *doublyTx[T]) Get(i int) (data T, err error) {
+	return tx.d.get(i)
+}
+
+func (tx *doublyTx[T]) Set(i int, data T) error {
+	return tx.d.set(i, data)
+}
+
+func (tx *doublyTx[T]) InsertAt(i int, data T) error {
+	return tx.d.insertAt(i, data)
+}
+
+func (tx *doublyTx[T]) RemoveAt(i int) (data T, err error) {
+	return tx.d.removeAt(i)
+}
+
+func (tx *doublyTx[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for tmp := tx.d.head; tmp != nil; tmp = tmp.Next {
+			if !yield(i, tmp.Data) {
+				return
+			}
+			i++
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package lists
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// txList is the part of Singly and Doubly exercised by TxTestSuite
+type txList interface {
+	List[int]
+	Values() iter.Seq[int]
+	Close() error
+	Done() <-chan struct{}
+	Do(fn func(tx ListTx[int]) error) error
+	OverflowStats() OverflowStats
+}
+
+type TxTestSuite struct {
+	suite.Suite
+	newList func(capacity int) txList
+}
+
+func TestTxTestSuite(t *testing.T) {
+	suite.Run(t, &TxTestSuite{newList: func(capacity int) txList {
+		if capacity > 0 {
+			return NewBoundedSingly[int](capacity, Block)
+		}
+		return NewSinglyOf[int]()
+	}})
+	suite.Run(t, &TxTestSuite{newList: func(capacity int) txList {
+		if capacity > 0 {
+			return NewBoundedDoubly[int](capacity, Block)
+		}
+		return NewDoublyOf[int]()
+	}})
+}
+
+func (suite *TxTestSuite) fill(l txList, values ...int) {
+	for _, v := range values {
+		l.PushTail(v)
+	}
+}
+
+func (suite *TxTestSuite) items(seq iter.Seq2[int, int]) (items []int) {
+	for _, item := range seq {
+		items = append(items, item)
+	}
+	return
+}
+
+func (suite *TxTestSuite) TestCommit() {
+	l := suite.newList(0)
+	suite.fill(l, 1, 2, 3)
+	var popped []int
+	err := l.Do(func(tx ListTx[int]) error {
+		if tx.Size() < 2 {
+			return nil
+		}
+		for i := 0; i < 2; i++ {
+			v, err := tx.PopHead()
+			assert.NoError(suite.T(), err)
+			popped = append(popped, v)
+		}
+		assert.NoError(suite.T(), tx.PushTail(4))
+		return nil
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []int{1, 2}, popped)
+	assert.Equal(suite.T(), []int{3, 4}, slices.Collect(l.Values()))
+}
+
+func (suite *TxTestSuite) TestRollback() {
+	l := suite.newList(0)
+	suite.fill(l, 1, 2, 3, 4)
+	errAbort := errors.New("abort")
+	err := l.Do(func(tx ListTx[int]) error {
+		tx.PopHead()
+		tx.PopTail()
+		assert.NoError(suite.T(), tx.Set(0, 20))
+		assert.NoError(suite.T(), tx.InsertAt(1, 7))
+		tx.PushHead(9)
+		assert.Equal(suite.T(), 1, tx.Delete(1, func(data int) bool { return data == 3 }))
+		assert.Equal(suite.T(), []int{9, 20, 7}, suite.items(tx.All()))
+		return errAbort
+	})
+	assert.Equal(suite.T(), errAbort, err)
+	assert.Equal(suite.T(), 4, l.Size())
+	assert.Equal(suite.T(), []int{1, 2, 3, 4}, slices.Collect(l.Values()))
+
+	// the list is still fully usable from both ends
+	v, _ := l.PopTail()
+	assert.Equal(suite.T(), 4, v)
+	l.PushTail(5)
+	assert.Equal(suite.T(), []int{1, 2, 3, 5}, slices.Collect(l.Values()))
+}
+
+func (suite *TxTestSuite) TestRollbackOnPanic() {
+	l := suite.newList(0)
+	suite.fill(l, 1, 2)
+	assert.Panics(suite.T(), func() {
+		l.Do(func(tx ListTx[int]) error {
+			tx.PopHead()
+			panic("boom")
+		})
+	})
+	assert.Equal(suite.T(), []int{1, 2}, slices.Collect(l.Values()))
+}
+
+func (suite *TxTestSuite) TestPushDoesNotWait() {
+	l := suite.newList(2)
+	suite.fill(l, 1, 2)
+	err := l.Do(func(tx ListTx[int]) error {
+		return tx.PushTail(3)
+	})
+	assert.IsType(suite.T(), CapacityExceededError(""), err)
+	assert.Equal(suite.T(), []int{1, 2}, slices.Collect(l.Values()))
+	assert.Equal(suite.T(), OverflowStats{}, l.OverflowStats(), "rolled back rejections should not be counted")
+}
+
+func (suite *TxTestSuite) TestPopEmpty() {
+	l := suite.newList(0)
+	err := l.Do(func(tx ListTx[int]) error {
+		assert.True(suite.T(), tx.IsEmpty())
+		_, err := tx.PopTail()
+		return err
+	})
+	assert.IsType(suite.T(), EmptyListError(""), err)
+}
+
+func (suite *TxTestSuite) TestRolledBackDrainKeepsDoneOpen() {
+	l := suite.newList(0)
+	suite.fill(l, 1)
+	l.Close()
+	l.Do(func(tx ListTx[int]) error {
+		tx.PopHead()
+		return errors.New("abort")
+	})
+	select {
+	case <-l.Done():
+		suite.T().Fatal("Done closed by a rolled back transaction")
+	default:
+	}
+
+	l.Do(func(tx ListTx[int]) error {
+		tx.PopHead()
+		return nil
+	})
+	select {
+	case <-l.Done():
+	default:
+		suite.T().Fatal("Done not closed by a committed drain")
+	}
+}
+
+func TestTxRollbackRestoresEvicted(t *testing.T) {
+	s := NewBoundedSingly[int](3, Evict)
+	d := NewBoundedDoubly[int](3, Evict)
+	for _, l := range []txList{s, d} {
+		for i := 1; i <= 3; i++ {
+			l.PushTail(i)
+		}
+		err := l.Do(func(tx ListTx[int]) error {
+			tx.PushTail(4)
+			tx.PushHead(5)
+			var items []int
+			for _, item := range tx.All() {
+				items = append(items, item)
+			}
+			assert.Equal(t, []int{5, 2, 3}, items)
+			return errors.New("abort")
+		})
+		assert.Error(t, err)
+		assert.Equal(t, []int{1, 2, 3}, slices.Collect(l.Values()))
+		assert.Equal(t, OverflowStats{}, l.OverflowStats())
+	}
+}
+
+func TestTxRollbackRestoresElements(t *testing.T) {
+	d := NewDoublyOf[int]()
+	e1, _ := d.PushTailElement(1)
+	e2, _ := d.PushTailElement(2)
+	d.Do(func(tx ListTx[int]) error {
+		tx.PopHead()
+		tx.PushTail(3)
+		return errors.New("abort")
+	})
+	v, err := d.Value(e1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+	assert.NoError(t, d.MoveToTail(e1))
+	assert.Equal(t, []int{2, 1}, slices.Collect(d.Values()))
+	_, err = d.Remove(e2)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, slices.Collect(d.Values()))
+}
+
+func TestTxRollbackWakesWaiters(t *testing.T) {
+	s := NewSinglyOf[int]()
+	got := make(chan int)
+	go func() {
+		v, _ := s.PopHeadWait(context.Background())
+		got <- v
+	}()
+	waitForWaiters(s.rwLock, s.notEmpty, 1)
+	s.Do(func(tx ListTx[int]) error {
+		tx.PushTail(1)
+		return errors.New("abort")
+	})
+	s.PushTail(2)
+	assert.Equal(t, 2, <-got)
+}
@@ -0,0 +1,59 @@
+package lists
+
+// UntypedSingly is the list of interface{} returned by NewSingly, for code
+// written before Singly took a type parameter.  It is a Singly[interface{}]
+// whose PushHead and PushTail keep their original signatures and return
+// nothing, so method values and interfaces written against them still work.
+// Use its Singly field where a Stack, Queue, Deque or List is needed.
+type UntypedSingly struct {
+	*Singly[interface{}]
+}
+
+// PushHead adds data to the front of the list.  It has no error to return,
+// so it panics if the item can't be added.
+//
+// Runtime: O(1)
+func (s *UntypedSingly) PushHead(data interface{}) {
+	if err := s.Singly.PushHead(data); err != nil {
+		panic(err)
+	}
+}
+
+// PushTail adds data to the back of the list.  It has no error to return, so
+// it panics if the item can't be added.
+//
+// Runtime: O(1)
+func (s *UntypedSingly) PushTail(data interface{}) {
+	if err := s.Singly.PushTail(data); err != nil {
+		panic(err)
+	}
+}
+
+// UntypedDoubly is the list of interface{} returned by NewDoubly, for code
+// written before Doubly took a type parameter.  It is a Doubly[interface{}]
+// whose PushHead and PushTail keep their original signatures and return
+// nothing, so method values and interfaces written against them still work.
+// Use its Doubly field where a Stack, Queue, Deque or List is needed.
+type UntypedDoubly struct {
+	*Doubly[interface{}]
+}
+
+// PushHead adds data to the front of the list.  It has no error to return,
+// so it panics if the item can't be added.
+//
+// Runtime: O(1)
+func (d *UntypedDoubly) PushHead(data interface{}) {
+	if err := d.Doubly.PushHead(data); err != nil {
+		panic(err)
+	}
+}
+
+// PushTail adds data to the back of the list.  It has no error to return, so
+// it panics if the item can't be added.
+//
+// Runtime: O(1)
+func (d *UntypedDoubly) PushTail(data interface{}) {
+	if err := d.Doubly.PushTail(data); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,29 @@
+package lists
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUntypedPushSignatures(t *testing.T) {
+	// code written before type parameters can still hold the push methods
+	// of the untyped lists as func(interface{})
+	s := NewSingly()
+	d := NewDoubly()
+	pushes := []func(interface{}){s.PushHead, s.PushTail, d.PushHead, d.PushTail}
+	for i, push := range pushes {
+		push(i)
+	}
+	assert.Equal(t, 2, s.Size())
+	assert.Equal(t, 2, d.Size())
+	item, err := s.PopHead()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, item.(int))
+	item, err = d.PopTail()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, item.(int))
+
+	var stack Stack[interface{}] = s.Singly
+	assert.NoError(t, stack.PushHead(4))
+}